@@ -0,0 +1,179 @@
+package mobiledetect
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/houseme/mobiledetect/ua"
+)
+
+// AnomalySeverity ranks how strongly an anomaly hints at a spoofed User-Agent.
+type AnomalySeverity int
+
+const (
+	// AnomalyLow findings are also produced by legitimate setups, such as a "desktop site" mode.
+	AnomalyLow AnomalySeverity = iota + 1
+	// AnomalyMedium findings are unusual for an unmodified browser.
+	AnomalyMedium
+	// AnomalyHigh findings are impossible for an unmodified browser.
+	AnomalyHigh
+)
+
+func (s AnomalySeverity) String() string {
+	switch s {
+	case AnomalyLow:
+		return "low"
+	case AnomalyMedium:
+		return "medium"
+	case AnomalyHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+// AnomalyKind identifies an inconsistency.
+type AnomalyKind string
+
+const (
+	// AnomalyPlatformMismatch is a Sec-CH-UA-Platform disagreeing with the OS of the User-Agent.
+	AnomalyPlatformMismatch AnomalyKind = "platform-mismatch"
+	// AnomalyMobileMismatch is a Sec-CH-UA-Mobile disagreeing with the device type of the User-Agent.
+	AnomalyMobileMismatch AnomalyKind = "mobile-mismatch"
+	// AnomalyEngineMismatch is a non Chromium User-Agent, or any iOS one, sending Sec-CH-UA.
+	AnomalyEngineMismatch AnomalyKind = "engine-mismatch"
+	// AnomalyBrandMismatch is a Chromium version in Sec-CH-UA disagreeing with the User-Agent.
+	AnomalyBrandMismatch AnomalyKind = "brand-mismatch"
+	// AnomalyImpossibleVersion is a browser version which never shipped on the OS version.
+	AnomalyImpossibleVersion AnomalyKind = "impossible-version"
+	// AnomalyWebKitMismatch is a Safari version which never shipped with the WebKit version.
+	AnomalyWebKitMismatch AnomalyKind = "webkit-mismatch"
+)
+
+// Anomaly is an inconsistency found in the User-Agent and the client hints of a request.
+type Anomaly struct {
+	Kind     AnomalyKind     `json:"kind"`
+	Severity AnomalySeverity `json:"severity"`
+	Detail   string          `json:"detail"`
+}
+
+// chromeOSLimits are the last Chrome major versions shipped on old OS versions.
+var chromeOSLimits = []struct {
+	os      string
+	version string
+	chrome  int
+}{
+	{ua.Windows, "6.0", 49},
+	{ua.Windows, "6.3", 109},
+	{ua.MacOS, "10.10", 87},
+	{ua.MacOS, "10.12", 103},
+	{ua.MacOS, "10.14", 116},
+	{ua.Android, "4.4", 95},
+	{ua.Android, "6", 106},
+	{ua.Android, "7.1", 119},
+}
+
+// platformNames maps the Sec-CH-UA-Platform values to the OS names returned by OS.
+var platformNames = map[string]string{
+	"android":     ua.Android,
+	"chrome os":   ua.ChromeOS,
+	"chromium os": ua.ChromeOS,
+	"ios":         ua.IOS,
+	"linux":       ua.Linux,
+	"macos":       ua.MacOS,
+	"windows":     ua.Windows,
+}
+
+var brandRegex = regexp.MustCompile(`"([^"]*)"\s*;\s*v\s*=\s*"([^"]*)"`)
+
+// Anomalies returns the inconsistencies between the User-Agent, its versions and the client hints,
+// for risk scoring. None of them proves the request is spoofed.
+func (md *MobileDetect) Anomalies() []Anomaly {
+	var anomalies []Anomaly
+	add := func(kind AnomalyKind, severity AnomalySeverity, detail string) {
+		anomalies = append(anomalies, Anomaly{Kind: kind, Severity: severity, Detail: detail})
+	}
+
+	agent := ua.New(md.userAgent)
+	system, systemVersion := md.OS()
+	engine, _ := agent.Engine()
+	chrome := md.VersionKey(PropChrome)
+
+	if platform := hintToken(md.header("Sec-CH-UA-Platform")); "" != platform && "" != system {
+		if name, ok := platformNames[platform]; ok && name != system {
+			severity := AnomalyHigh
+			if ua.Android == name && ua.Linux == system {
+				severity = AnomalyLow
+			}
+			add(AnomalyPlatformMismatch, severity, "Sec-CH-UA-Platform "+platform+" on "+system)
+		}
+	}
+
+	if mobile := strings.TrimSpace(md.header("Sec-CH-UA-Mobile")); "" != mobile {
+		switch deviceType := md.detectedDeviceType(); {
+		case "?1" == mobile && DeviceTypeDesktop == deviceType:
+			add(AnomalyMobileMismatch, AnomalyMedium, "Sec-CH-UA-Mobile ?1 on a desktop User-Agent")
+		case "?0" == mobile && DeviceTypeMobile == deviceType:
+			add(AnomalyMobileMismatch, AnomalyLow, "Sec-CH-UA-Mobile ?0 on a phone User-Agent")
+		}
+	}
+
+	if brands := brandRegex.FindAllStringSubmatch(md.header("Sec-CH-UA"), -1); len(brands) > 0 {
+		if ua.IOS == system || md.Is("iOS") {
+			add(AnomalyEngineMismatch, AnomalyHigh, "Sec-CH-UA sent by an iOS User-Agent, iOS browsers use WebKit")
+		} else if "" == chrome {
+			add(AnomalyEngineMismatch, AnomalyHigh, "Sec-CH-UA sent by a "+engine+" User-Agent without Chrome")
+		} else {
+			for _, brand := range brands {
+				if "Chromium" == brand[1] && compareMajor(brand[2], chrome) != 0 {
+					add(AnomalyBrandMismatch, AnomalyHigh, "Sec-CH-UA Chromium "+brand[2]+" with Chrome/"+chrome)
+				}
+			}
+		}
+	}
+
+	if "" != chrome && "" != systemVersion {
+		major := majorVersion(chrome)
+		for _, limit := range chromeOSLimits {
+			if limit.os == system && versionWithin(systemVersion, limit.version) && major > limit.chrome {
+				add(AnomalyImpossibleVersion, AnomalyHigh, "Chrome "+chrome+" on "+system+" "+systemVersion)
+				break
+			}
+		}
+	}
+
+	if ua.Safari == agent.Name() {
+		safari := md.VersionKey(PropVersion)
+		webkit := md.VersionKey(PropWebkit)
+		switch {
+		case "" == webkit:
+			add(AnomalyWebKitMismatch, AnomalyHigh, "Safari without AppleWebKit")
+		case majorVersion(safari) >= 10 && majorVersion(webkit) < 600:
+			add(AnomalyWebKitMismatch, AnomalyMedium, "Safari "+safari+" with AppleWebKit "+webkit)
+		}
+		// Since iOS 26 the OS token is frozen at 18_6, Version still telling the Safari release.
+		frozen := compareVersions(systemVersion, "18.6") >= 0 && compareMajor(safari, systemVersion) > 0
+		if ua.IOS == system && "" != safari && majorVersion(systemVersion) >= 8 && compareMajor(safari, systemVersion) != 0 && !frozen {
+			add(AnomalyImpossibleVersion, AnomalyMedium, "Safari "+safari+" on iOS "+systemVersion)
+		}
+	}
+
+	return anomalies
+}
+
+func majorVersion(version string) int {
+	if parts := versionParts(version); len(parts) > 0 {
+		return parts[0]
+	}
+	return 0
+}
+
+func compareMajor(a, b string) int {
+	x, y := majorVersion(a), majorVersion(b)
+	if x < y {
+		return -1
+	}
+	if x > y {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestAnomalies(t *testing.T) {
+	pixelUserAgent := `Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36`
+	pixelHints := map[string]string{
+		"Sec-CH-UA":          `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`,
+		"Sec-CH-UA-Mobile":   "?1",
+		"Sec-CH-UA-Platform": `"Android"`,
+	}
+	tests := []struct {
+		name      string
+		userAgent string
+		headers   map[string]string
+		expected  map[AnomalyKind]AnomalySeverity
+	}{
+		{"consistent Chrome", pixelUserAgent, pixelHints, nil},
+		{"consistent Safari", iPhoneUserAgent, nil, nil},
+		{"consistent desktop", desktopUserAgent, map[string]string{
+			"Sec-CH-UA":          `"Chromium";v="120", "Google Chrome";v="120", "Not-A.Brand";v="99"`,
+			"Sec-CH-UA-Mobile":   "?0",
+			"Sec-CH-UA-Platform": `"Windows"`,
+		}, nil},
+		{"platform", desktopUserAgent, map[string]string{"Sec-CH-UA-Platform": `"macOS"`},
+			map[AnomalyKind]AnomalySeverity{AnomalyPlatformMismatch: AnomalyHigh}},
+		{"desktop mode", `Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`,
+			map[string]string{"Sec-CH-UA-Platform": `"Android"`, "Sec-CH-UA-Mobile": "?0"},
+			map[AnomalyKind]AnomalySeverity{AnomalyPlatformMismatch: AnomalyLow}},
+		{"mobile hint", desktopUserAgent, map[string]string{"Sec-CH-UA-Mobile": "?1"},
+			map[AnomalyKind]AnomalySeverity{AnomalyMobileMismatch: AnomalyMedium}},
+		{"iPhone with Blink hints", iPhoneUserAgent, pixelHints,
+			map[AnomalyKind]AnomalySeverity{AnomalyEngineMismatch: AnomalyHigh, AnomalyPlatformMismatch: AnomalyHigh}},
+		{"brand", pixelUserAgent, map[string]string{"Sec-CH-UA": `"Chromium";v="99", "Google Chrome";v="99"`},
+			map[AnomalyKind]AnomalySeverity{AnomalyBrandMismatch: AnomalyHigh}},
+		{"Chrome on Windows 7", `Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36`, nil,
+			map[AnomalyKind]AnomalySeverity{AnomalyImpossibleVersion: AnomalyHigh}},
+		{"last Chrome on Windows 7", chrome109UserAgent, nil, nil},
+		{"Safari on old WebKit", `Mozilla/5.0 (iPhone; CPU iPhone OS 13_2_3 like Mac OS X) AppleWebKit/534.46 (KHTML, like Gecko) Version/13.0.3 Mobile/15E148 Safari/604.1`, nil,
+			map[AnomalyKind]AnomalySeverity{AnomalyWebKitMismatch: AnomalyMedium}},
+		{"Safari on another iOS", `Mozilla/5.0 (iPhone; CPU iPhone OS 13_2_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1`, nil,
+			map[AnomalyKind]AnomalySeverity{AnomalyImpossibleVersion: AnomalyMedium}},
+		{"Safari on iOS 26", `Mozilla/5.0 (iPhone; CPU iPhone OS 18_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.0 Mobile/15E148 Safari/604.1`, nil, nil},
+		{"older Safari on iOS 18.6", `Mozilla/5.0 (iPhone; CPU iPhone OS 18_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1`, nil,
+			map[AnomalyKind]AnomalySeverity{AnomalyImpossibleVersion: AnomalyMedium}},
+	}
+
+	for _, test := range tests {
+		anomalies := New(newTestRequest(test.userAgent, test.headers), nil).Anomalies()
+		found := make(map[AnomalyKind]AnomalySeverity)
+		for _, anomaly := range anomalies {
+			found[anomaly.Kind] = anomaly.Severity
+		}
+		if len(found) != len(test.expected) {
+			t.Errorf("%s: expected %v got %+v", test.name, test.expected, anomalies)
+			continue
+		}
+		for kind, severity := range test.expected {
+			if severity != found[kind] {
+				t.Errorf("%s: expected %v got %+v", test.name, test.expected, anomalies)
+			}
+		}
+	}
+}
+
+func TestAnomaliesIgnoreOverride(t *testing.T) {
+	md := New(newTestRequest(desktopUserAgent, map[string]string{"Sec-CH-UA-Mobile": "?1"}), nil)
+	md.SetOverride(DeviceTypeMobile)
+	if anomalies := md.Anomalies(); 1 != len(anomalies) || AnomalyMobileMismatch != anomalies[0].Kind {
+		t.Errorf("Expected a mobile mismatch despite the override, got %+v", anomalies)
+	}
+}
@@ -0,0 +1,285 @@
+package mobiledetect
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Capabilities are the client properties measured in the browser by the beacon script,
+// which the User-Agent and the client hints don't tell.
+type Capabilities struct {
+	// Touch reports whether the device has a touch screen.
+	Touch bool `json:"touch"`
+	// MaxTouchPoints is navigator.maxTouchPoints, above 1 on iPads in desktop mode.
+	MaxTouchPoints int `json:"maxTouchPoints"`
+	// ScreenWidth and ScreenHeight are the screen size in CSS pixels.
+	ScreenWidth  int `json:"screenWidth"`
+	ScreenHeight int `json:"screenHeight"`
+	// ViewportWidth and ViewportHeight are the window size in CSS pixels.
+	ViewportWidth  int `json:"viewportWidth"`
+	ViewportHeight int `json:"viewportHeight"`
+	// DPR is the device pixel ratio.
+	DPR float64 `json:"dpr"`
+	// Segments is the number of viewport segments, above 1 on unfolded dual-screen devices.
+	Segments int `json:"segments"`
+}
+
+// beaconVersion prefixes the cookie values, cookies of other versions are ignored.
+const beaconVersion = "c1"
+
+// tabletSmallestWidth is the smallest screen side, in CSS pixels, from which a touch device is a tablet.
+const tabletSmallestWidth = 600
+
+// SetCapabilities sets the capabilities measured by the beacon, refining DeviceType, ActualDeviceType
+// and Screen. An iPad in desktop mode stays a desktop for DeviceType, see RequestedDesktop.
+func (md *MobileDetect) SetCapabilities(c Capabilities) *MobileDetect {
+	md.capabilities = &c
+	return md
+}
+
+// Capabilities returns the capabilities measured by the beacon, if any.
+func (md *MobileDetect) Capabilities() (Capabilities, bool) {
+	if nil == md.capabilities {
+		return Capabilities{}, false
+	}
+	return *md.capabilities, true
+}
+
+// measuredDeviceType refines the device type from the capabilities: an iPad in desktop mode
+// claims to be a Mac but has touch points, an unfolded foldable claims to be a phone but has
+// a tablet sized screen. It returns an empty device type when there is nothing to refine.
+func (md *MobileDetect) measuredDeviceType(detected DeviceType) DeviceType {
+	c, ok := md.Capabilities()
+	if !ok {
+		return ""
+	}
+	switch detected {
+	case DeviceTypeDesktop:
+		if c.MaxTouchPoints > 1 && md.match(`Macintosh`) {
+			return DeviceTypeTablet
+		}
+	case DeviceTypeMobile:
+		smallest := c.ScreenWidth
+		if c.ScreenHeight < smallest {
+			smallest = c.ScreenHeight
+		}
+		if c.Touch && (smallest >= tabletSmallestWidth || c.Segments > 1) {
+			return DeviceTypeTablet
+		}
+	}
+	return ""
+}
+
+// Beacon receives the capabilities measured by its Script and stores them in an HMAC signed cookie,
+// which its Handler folds into the detection of the next requests.
+type Beacon struct {
+	// Path is where Script posts the measures, the Beacon must be mounted there.
+	Path string
+	// CookieName is the name of the cookie holding the capabilities.
+	CookieName string
+	// CookiePath and CookieDomain scope the cookie.
+	CookiePath   string
+	CookieDomain string
+	// MaxAge is how long the capabilities are kept.
+	MaxAge time.Duration
+	// Secret signs the cookie. Without it the capabilities are neither stored nor read.
+	Secret []byte
+
+	rules *rules
+	now   func() time.Time
+}
+
+// NewBeacon creates a Beacon mounted on /mobiledetect/beacon keeping the capabilities for 30 days,
+// using the given rules, or the default ones when nil.
+func NewBeacon(rules *rules, secret []byte) *Beacon {
+	return &Beacon{
+		Path:       "/mobiledetect/beacon",
+		CookieName: "mobiledetect_caps",
+		CookiePath: "/",
+		MaxAge:     30 * 24 * time.Hour,
+		Secret:     secret,
+		rules:      rules,
+	}
+}
+
+// Script returns the JavaScript measuring the capabilities and posting them to Path,
+// to be embedded in the pages: <script>{{.BeaconScript}}</script>.
+func (b *Beacon) Script() template.JS {
+	path, _ := json.Marshal(b.Path)
+	return template.JS(`(function(){var n=navigator,s=screen,w=window,v=w.viewport,` +
+		`d={t:("ontouchstart" in w||n.maxTouchPoints>0)?1:0,p:n.maxTouchPoints||0,sw:s.width,sh:s.height,` +
+		`vw:w.innerWidth,vh:w.innerHeight,dpr:w.devicePixelRatio||1,seg:v&&v.segments?v.segments.length:1},` +
+		`q=Object.keys(d).map(function(k){return k+"="+encodeURIComponent(d[k])}).join("&");` +
+		`if(n.sendBeacon){n.sendBeacon(` + string(path) + `,new Blob([q],{type:"application/x-www-form-urlencoded"}))}` +
+		`else{var x=new XMLHttpRequest();x.open("POST",` + string(path) + `);` +
+		`x.setRequestHeader("Content-Type","application/x-www-form-urlencoded");x.send(q)}})();`)
+}
+
+// ServeHTTP receives the measures posted by Script and stores them in the cookie.
+func (b *Beacon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if http.MethodPost != r.Method {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1024)
+	if err := r.ParseForm(); nil != err {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	c, ok := parseCapabilities(r.PostForm.Get)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if len(b.Secret) > 0 && "" != b.CookieName {
+		expires := b.clock().Add(b.MaxAge)
+		http.SetCookie(w, &http.Cookie{
+			Name:     b.CookieName,
+			Value:    b.sign(c, expires),
+			Path:     b.CookiePath,
+			Domain:   b.CookieDomain,
+			Expires:  expires,
+			MaxAge:   int(b.MaxAge.Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseCapabilities reads the measures posted by Script, rejecting the implausible ones.
+func parseCapabilities(get func(string) string) (Capabilities, bool) {
+	var c Capabilities
+	ints := []struct {
+		name string
+		dst  *int
+		max  int
+	}{
+		{"p", &c.MaxTouchPoints, 32},
+		{"sw", &c.ScreenWidth, 100000},
+		{"sh", &c.ScreenHeight, 100000},
+		{"vw", &c.ViewportWidth, 100000},
+		{"vh", &c.ViewportHeight, 100000},
+		{"seg", &c.Segments, 8},
+	}
+	for _, field := range ints {
+		n, err := strconv.Atoi(get(field.name))
+		if nil != err || n < 0 || n > field.max {
+			return Capabilities{}, false
+		}
+		*field.dst = n
+	}
+
+	switch get("t") {
+	case "1":
+		c.Touch = true
+	case "0":
+	default:
+		return Capabilities{}, false
+	}
+
+	dpr, err := strconv.ParseFloat(get("dpr"), 64)
+	if nil != err || dpr <= 0 || dpr > 10 {
+		return Capabilities{}, false
+	}
+	c.DPR = math.Round(dpr*100) / 100
+	return c, true
+}
+
+// Apply sets on md the capabilities stored in the request cookie, if any and valid.
+// Apply on a nil Beacon does nothing.
+func (b *Beacon) Apply(r *http.Request, md *MobileDetect) {
+	if nil == b || len(b.Secret) == 0 || "" == b.CookieName {
+		return
+	}
+	if cookie, err := r.Cookie(b.CookieName); nil == err {
+		if c, ok := b.verify(cookie.Value); ok {
+			md.SetCapabilities(c)
+		}
+	}
+}
+
+// Handler applies the stored capabilities and serves the request with next,
+// the MobileDetect being stored in the request context, see FromContext.
+func (b *Beacon) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md := detect(r, b.rules)
+		b.Apply(r, md)
+		w.Header().Add("Vary", "Cookie")
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), md)))
+	})
+}
+
+// sign returns the cookie value: the version, the capabilities, their expiry and their signature,
+// dot separated, e.g. c1.1_5_1024_1366_1024_1292_200_1.1700000000.<signature>.
+func (b *Beacon) sign(c Capabilities, expires time.Time) string {
+	touch := 0
+	if c.Touch {
+		touch = 1
+	}
+	fields := []int{touch, c.MaxTouchPoints, c.ScreenWidth, c.ScreenHeight, c.ViewportWidth, c.ViewportHeight,
+		int(math.Round(c.DPR * 100)), c.Segments}
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		values[i] = strconv.Itoa(field)
+	}
+	payload := beaconVersion + "." + strings.Join(values, "_") + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + b.mac(payload)
+}
+
+func (b *Beacon) verify(value string) (Capabilities, bool) {
+	i := strings.LastIndex(value, ".")
+	if -1 == i {
+		return Capabilities{}, false
+	}
+	payload, signature := value[:i], value[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(b.mac(payload))) {
+		return Capabilities{}, false
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 || beaconVersion != parts[0] {
+		return Capabilities{}, false
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if nil != err || b.clock().Unix() >= expires {
+		return Capabilities{}, false
+	}
+
+	fields := strings.Split(parts[1], "_")
+	if len(fields) != 8 {
+		return Capabilities{}, false
+	}
+	names := []string{"t", "p", "sw", "sh", "vw", "vh", "dpr", "seg"}
+	values := make(map[string]string, len(names))
+	for i, name := range names {
+		values[name] = fields[i]
+	}
+	if dpr, err := strconv.Atoi(values["dpr"]); nil == err {
+		values["dpr"] = strconv.FormatFloat(float64(dpr)/100, 'f', -1, 64)
+	}
+	return parseCapabilities(func(name string) string { return values[name] })
+}
+
+func (b *Beacon) mac(payload string) string {
+	m := hmac.New(sha256.New, b.Secret)
+	m.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+func (b *Beacon) clock() time.Time {
+	if nil != b.now {
+		return b.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,122 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const macSafariUserAgent = `Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15`
+
+func postBeacon(b *Beacon, form url.Values) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", b.Path, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestBeaconRoundTrip(t *testing.T) {
+	b := NewBeacon(nil, []byte("secret"))
+	rec := postBeacon(b, url.Values{
+		"t": {"1"}, "p": {"5"}, "sw": {"1024"}, "sh": {"1366"}, "vw": {"1024"}, "vh": {"1292"}, "dpr": {"2"}, "seg": {"1"},
+	})
+	if http.StatusNoContent != rec.Code {
+		t.Fatalf("Expected 204 got %d", rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0].Value, "c1.1_5_1024_1366_1024_1292_200_1.") {
+		t.Fatalf("Unexpected cookie: %v", cookies)
+	}
+
+	var deviceType DeviceType
+	var screen Screen
+	h := b.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md, _ := FromContext(r.Context())
+		deviceType, screen = md.ActualDeviceType(), md.Screen()
+	}))
+	r := newTestRequest(macSafariUserAgent, nil)
+	r.AddCookie(cookies[0])
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if DeviceTypeTablet != deviceType {
+		t.Errorf("iPad in desktop mode should be a tablet, got %s", deviceType)
+	}
+	if 1024 != screen.ViewportWidth || 1292 != screen.ViewportHeight || 2 != screen.DPR || !screen.Hinted {
+		t.Errorf("Unexpected screen: %+v", screen)
+	}
+}
+
+func TestBeaconRejectsInvalidMeasures(t *testing.T) {
+	b := NewBeacon(nil, []byte("secret"))
+	valid := url.Values{"t": {"0"}, "p": {"0"}, "sw": {"1920"}, "sh": {"1080"}, "vw": {"1920"}, "vh": {"960"}, "dpr": {"1"}, "seg": {"1"}}
+	for name, value := range map[string]string{"t": "yes", "p": "-1", "sw": "1e9", "dpr": "0", "seg": ""} {
+		form := url.Values{}
+		for k, v := range valid {
+			form[k] = v
+		}
+		form.Set(name, value)
+		if rec := postBeacon(b, form); http.StatusBadRequest != rec.Code {
+			t.Errorf("For %s=%q, expected 400 got %d", name, value, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, httptest.NewRequest("GET", b.Path, nil))
+	if http.StatusMethodNotAllowed != rec.Code {
+		t.Errorf("Expected 405 got %d", rec.Code)
+	}
+}
+
+func TestBeaconCookieIntegrity(t *testing.T) {
+	b := NewBeacon(nil, []byte("secret"))
+	now := time.Unix(1600000000, 0)
+	b.now = func() time.Time { return now }
+	c := Capabilities{Touch: true, MaxTouchPoints: 5, ScreenWidth: 1024, ScreenHeight: 1366, DPR: 2, Segments: 1}
+	valid := b.sign(c, now.Add(time.Hour))
+
+	if decoded, ok := b.verify(valid); !ok || c != decoded {
+		t.Errorf("Expected %+v got %+v", c, decoded)
+	}
+
+	tampered := strings.Replace(valid, "1_5_1024", "1_5_2048", 1)
+	other := NewBeacon(nil, []byte("other")).sign(c, now.Add(time.Hour))
+	expired := b.sign(c, now)
+	future := "c2" + strings.TrimPrefix(valid, "c1")
+	for name, value := range map[string]string{"tampered": tampered, "forged": other, "expired": expired, "version": future, "empty": ""} {
+		if _, ok := b.verify(value); ok {
+			t.Errorf("The %s cookie should be rejected", name)
+		}
+	}
+}
+
+func TestMeasuredDeviceType(t *testing.T) {
+	foldable := `Mozilla/5.0 (Linux; Android 14; SM-F946B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36`
+	tests := []struct {
+		userAgent    string
+		capabilities Capabilities
+		expected     DeviceType
+	}{
+		{macSafariUserAgent, Capabilities{MaxTouchPoints: 5, Touch: true}, DeviceTypeTablet},
+		{macSafariUserAgent, Capabilities{MaxTouchPoints: 0}, DeviceTypeDesktop},
+		{desktopUserAgent, Capabilities{MaxTouchPoints: 10, Touch: true}, DeviceTypeDesktop},
+		{foldable, Capabilities{Touch: true, ScreenWidth: 884, ScreenHeight: 1104}, DeviceTypeTablet},
+		{foldable, Capabilities{Touch: true, ScreenWidth: 412, ScreenHeight: 915}, DeviceTypeMobile},
+		{foldable, Capabilities{Touch: true, ScreenWidth: 412, ScreenHeight: 915, Segments: 2}, DeviceTypeTablet},
+	}
+	for _, test := range tests {
+		md := New(newTestRequest(test.userAgent, nil), nil).SetCapabilities(test.capabilities)
+		if deviceType := md.ActualDeviceType(); test.expected != deviceType {
+			t.Errorf("For %s with %+v, expected %s got %s", test.userAgent, test.capabilities, test.expected, deviceType)
+		}
+	}
+}
+
+func TestBeaconScript(t *testing.T) {
+	script := string(NewBeacon(nil, nil).Script())
+	if !strings.Contains(script, `"/mobiledetect/beacon"`) || !strings.Contains(script, "maxTouchPoints") {
+		t.Errorf("Unexpected script: %s", script)
+	}
+}
@@ -0,0 +1,393 @@
+package mobiledetect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/houseme/mobiledetect/ua"
+)
+
+// BotCategory groups crawlers by purpose.
+type BotCategory string
+
+const (
+	// BotCategorySearch are search engine crawlers.
+	BotCategorySearch BotCategory = "search"
+	// BotCategoryLinkPreview are the fetchers building link previews in chats and social networks.
+	BotCategoryLinkPreview BotCategory = "link-preview"
+	// BotCategoryScraper are SEO crawlers, HTTP libraries and headless browsers.
+	BotCategoryScraper BotCategory = "scraper"
+	// BotCategoryUnknown are the other crawlers.
+	BotCategoryUnknown BotCategory = "unknown"
+)
+
+// BotAction is what a BotPolicy does with a crawler request.
+type BotAction string
+
+const (
+	// BotAllow serves the request.
+	BotAllow BotAction = "allow"
+	// BotBlock answers 403 Forbidden.
+	BotBlock BotAction = "block"
+	// BotTarpit delays the response by BotPolicy.TarpitDelay before serving it.
+	BotTarpit BotAction = "tarpit"
+	// BotLite serves the request with BotPolicy.Lite.
+	BotLite BotAction = "lite"
+	// BotRateLimit serves the request unless the crawler exceeds BotPolicy.Rate, answering 429 Too Many Requests.
+	BotRateLimit BotAction = "ratelimit"
+)
+
+var botActions = []BotAction{BotAllow, BotBlock, BotTarpit, BotLite, BotRateLimit}
+
+var botCategories = []BotCategory{BotCategorySearch, BotCategoryLinkPreview, BotCategoryScraper, BotCategoryUnknown}
+
+// knownBots are the crawlers recognized by name, matched case-insensitively against the User-Agent.
+var knownBots = []struct {
+	name     string
+	category BotCategory
+}{
+	{"Googlebot", BotCategorySearch},
+	{"bingbot", BotCategorySearch},
+	{"Applebot", BotCategorySearch},
+	{"DuckDuckBot", BotCategorySearch},
+	{"YandexBot", BotCategorySearch},
+	{"Baiduspider", BotCategorySearch},
+	{"Slurp", BotCategorySearch},
+	{"facebookexternalhit", BotCategoryLinkPreview},
+	{"Twitterbot", BotCategoryLinkPreview},
+	{"Slackbot", BotCategoryLinkPreview},
+	{"LinkedInBot", BotCategoryLinkPreview},
+	{"WhatsApp", BotCategoryLinkPreview},
+	{"TelegramBot", BotCategoryLinkPreview},
+	{"Discordbot", BotCategoryLinkPreview},
+	{"Pinterestbot", BotCategoryLinkPreview},
+	{"redditbot", BotCategoryLinkPreview},
+	{"Embedly", BotCategoryLinkPreview},
+	{"AhrefsBot", BotCategoryScraper},
+	{"SemrushBot", BotCategoryScraper},
+	{"MJ12bot", BotCategoryScraper},
+	{"DotBot", BotCategoryScraper},
+	{"PetalBot", BotCategoryScraper},
+	{"Bytespider", BotCategoryScraper},
+	{"Scrapy", BotCategoryScraper},
+	{"python-requests", BotCategoryScraper},
+	{"curl", BotCategoryScraper},
+	{"Wget", BotCategoryScraper},
+	{"Go-http-client", BotCategoryScraper},
+	{"HeadlessChrome", BotCategoryScraper},
+	{"PhantomJS", BotCategoryScraper},
+}
+
+// BotRule applies an action to the crawlers of a category and/or name. Empty fields match any crawler.
+type BotRule struct {
+	Category BotCategory `json:"category"`
+	Name     string      `json:"name"`
+	Action   BotAction   `json:"action"`
+}
+
+// DefaultBotRules allow search engines, serve link previews the lite page, block scrapers
+// and rate limit the others.
+var DefaultBotRules = []BotRule{
+	{Category: BotCategorySearch, Action: BotAllow},
+	{Category: BotCategoryLinkPreview, Action: BotLite},
+	{Category: BotCategoryScraper, Action: BotBlock},
+	{Category: BotCategoryUnknown, Action: BotRateLimit},
+}
+
+// BotDecision is the outcome of a BotPolicy for a request.
+type BotDecision struct {
+	Bot      bool        `json:"bot"`
+	Category BotCategory `json:"category,omitempty"`
+	Name     string      `json:"name,omitempty"`
+	Action   BotAction   `json:"action"`
+}
+
+// BotPolicy is a middleware applying per-category actions to crawlers, see BotPolicy.Handler.
+type BotPolicy struct {
+	// Rules are evaluated in order, the first one matching the crawler decides.
+	// Crawlers matching none are allowed.
+	Rules []BotRule
+	// TarpitDelay is the delay of BotTarpit.
+	TarpitDelay time.Duration
+	// Rate is the number of requests per second a crawler name may send under BotRateLimit,
+	// with bursts of Burst requests. The unknown crawlers, whose name is chosen by the client,
+	// share the bucket of their category.
+	Rate  float64
+	Burst int
+	// Lite serves the BotLite requests. When nil they are served by the next handler,
+	// which finds the decision with BotDecisionFromContext.
+	Lite http.Handler
+	// Logger, when set, logs the decisions taken for crawlers.
+	Logger *log.Logger
+
+	rules    *rules
+	now      func() time.Time
+	mu       sync.Mutex
+	buckets  map[string]*tokenBucket
+	counters map[string]int64
+}
+
+// NewBotPolicy creates a BotPolicy with DefaultBotRules, a 5 seconds tarpit and a rate of one
+// request per second, using the given rules, or the default ones when nil.
+func NewBotPolicy(rules *rules) *BotPolicy {
+	return &BotPolicy{
+		Rules:       append([]BotRule{}, DefaultBotRules...),
+		TarpitDelay: 5 * time.Second,
+		Rate:        1,
+		Burst:       5,
+		rules:       rules,
+	}
+}
+
+// LoadBotPolicy reads a BotPolicy from JSON, starting from the NewBotPolicy defaults:
+//
+//	{
+//		"tarpitDelay": "10s",
+//		"rate": 0.5,
+//		"burst": 2,
+//		"rules": [{"name": "AhrefsBot", "action": "tarpit"}, {"category": "search", "action": "allow"}]
+//	}
+func LoadBotPolicy(r io.Reader, rules *rules) (*BotPolicy, error) {
+	var config struct {
+		TarpitDelay string    `json:"tarpitDelay"`
+		Rate        *float64  `json:"rate"`
+		Burst       *int      `json:"burst"`
+		Rules       []BotRule `json:"rules"`
+	}
+	if err := json.NewDecoder(r).Decode(&config); nil != err {
+		return nil, fmt.Errorf("mobiledetect: decoding bot policy: %v", err)
+	}
+
+	p := NewBotPolicy(rules)
+	if "" != config.TarpitDelay {
+		delay, err := time.ParseDuration(config.TarpitDelay)
+		if nil != err {
+			return nil, fmt.Errorf("mobiledetect: invalid tarpit delay: %v", err)
+		}
+		p.TarpitDelay = delay
+	}
+	if nil != config.Rate {
+		p.Rate = *config.Rate
+	}
+	if nil != config.Burst {
+		p.Burst = *config.Burst
+	}
+	if nil != config.Rules {
+		for _, rule := range config.Rules {
+			if !validBotAction(rule.Action) {
+				return nil, fmt.Errorf("mobiledetect: unknown bot action %q", rule.Action)
+			}
+			if "" != rule.Category && !validBotCategory(rule.Category) {
+				return nil, fmt.Errorf("mobiledetect: unknown bot category %q", rule.Category)
+			}
+		}
+		p.Rules = config.Rules
+	}
+	return p, nil
+}
+
+func validBotAction(action BotAction) bool {
+	for _, a := range botActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func validBotCategory(category BotCategory) bool {
+	for _, c := range botCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyBot returns the category and name of the crawler sending the request, if any.
+// Crawlers are recognized by name, by the bot utilities rules and by the ua package.
+func (md *MobileDetect) ClassifyBot() (category BotCategory, name string, ok bool) {
+	lower := strings.ToLower(md.userAgent)
+	for _, bot := range knownBots {
+		if strings.Contains(lower, strings.ToLower(bot.name)) {
+			return bot.category, bot.name, true
+		}
+	}
+
+	agent := ua.New(md.userAgent)
+	if !md.IsBot() && !agent.Bot() {
+		return "", "", false
+	}
+	if agent.IsGoogleBot() {
+		return BotCategorySearch, ua.Googlebot, true
+	}
+	if agent.IsTwitterBot() || agent.IsFacebookBot() {
+		return BotCategoryLinkPreview, agent.Name(), true
+	}
+	return BotCategoryUnknown, agent.Name(), true
+}
+
+// Decide returns the decision of the policy for the request, without recording it.
+func (p *BotPolicy) Decide(md *MobileDetect) BotDecision {
+	category, name, ok := md.ClassifyBot()
+	if !ok {
+		return BotDecision{Action: BotAllow}
+	}
+	decision := BotDecision{Bot: true, Category: category, Name: name, Action: BotAllow}
+	for _, rule := range p.Rules {
+		if "" != rule.Category && rule.Category != category {
+			continue
+		}
+		if "" != rule.Name && !strings.EqualFold(rule.Name, name) {
+			continue
+		}
+		decision.Action = rule.Action
+		break
+	}
+	return decision
+}
+
+// bucket returns the name of the rate limiting bucket of the crawler: its name when known,
+// its category otherwise since a client could mint a bucket per request.
+func (d BotDecision) bucket() string {
+	if BotCategoryUnknown == d.Category {
+		return string(d.Category)
+	}
+	return d.Name
+}
+
+type botDecisionKey struct{}
+
+// BotDecisionFromContext returns the decision stored in ctx by BotPolicy.Handler, if any.
+func BotDecisionFromContext(ctx context.Context) (BotDecision, bool) {
+	decision, ok := ctx.Value(botDecisionKey{}).(BotDecision)
+	return decision, ok
+}
+
+// Handler applies the policy to the crawler requests and serves the others with next.
+func (p *BotPolicy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md := detect(r, p.rules)
+		decision := p.Decide(md)
+		if !decision.Bot {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if BotRateLimit == decision.Action {
+			if wait, ok := p.take(decision.bucket()); !ok {
+				p.record(r, decision, "rate limited")
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+		}
+		p.record(r, decision, "")
+
+		ctx := context.WithValue(NewContext(r.Context(), md), botDecisionKey{}, decision)
+		r = r.WithContext(ctx)
+		switch decision.Action {
+		case BotBlock:
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		case BotTarpit:
+			timer := time.NewTimer(p.TarpitDelay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				next.ServeHTTP(w, r)
+			case <-ctx.Done():
+			}
+		case BotLite:
+			if nil != p.Lite {
+				p.Lite.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// record counts and logs the decision, outcome tells when the action was not carried out as is.
+func (p *BotPolicy) record(r *http.Request, decision BotDecision, outcome string) {
+	action := string(decision.Action)
+	if "" != outcome {
+		action += " (" + outcome + ")"
+	}
+
+	p.mu.Lock()
+	if nil == p.counters {
+		p.counters = make(map[string]int64)
+	}
+	p.counters[string(decision.Category)+"."+action]++
+	p.mu.Unlock()
+
+	if nil != p.Logger {
+		p.Logger.Printf("mobiledetect: %s bot %s %s %s: %s", decision.Category, decision.Name, r.Method, r.URL.Path, action)
+	}
+}
+
+// Stats returns the number of decisions taken, keyed by category and action, e.g. "scraper.block"
+// or "unknown.ratelimit (rate limited)".
+func (p *BotPolicy) Stats() map[string]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make(map[string]int64, len(p.counters))
+	for k, v := range p.counters {
+		stats[k] = v
+	}
+	return stats
+}
+
+// take consumes a token of the named bucket, returning the wait for the next one when empty.
+func (p *BotPolicy) take(name string) (time.Duration, bool) {
+	if p.Rate <= 0 {
+		return 0, true
+	}
+	now := p.clock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if nil == p.buckets {
+		p.buckets = make(map[string]*tokenBucket)
+	}
+	burst := math.Max(1, float64(p.Burst))
+	b, ok := p.buckets[name]
+	if !ok {
+		b = &tokenBucket{tokens: burst, last: now}
+		p.buckets[name] = b
+	}
+	return b.take(now, p.Rate, burst)
+}
+
+func (p *BotPolicy) clock() time.Time {
+	if nil != p.now {
+		return p.now()
+	}
+	return time.Now()
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(now time.Time, rate, burst float64) (time.Duration, bool) {
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second)), false
+}
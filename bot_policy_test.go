@@ -0,0 +1,135 @@
+package mobiledetect
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassifyBot(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		category  BotCategory
+		name      string
+		ok        bool
+	}{
+		{`Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`, BotCategorySearch, "Googlebot", true},
+		{`Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)`, BotCategorySearch, "bingbot", true},
+		{`facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)`, BotCategoryLinkPreview, "facebookexternalhit", true},
+		{`Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)`, BotCategoryLinkPreview, "Slackbot", true},
+		{`Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)`, BotCategoryScraper, "AhrefsBot", true},
+		{`python-requests/2.31.0`, BotCategoryScraper, "python-requests", true},
+		{`Mozilla/5.0 (compatible; ExampleCrawler/1.0; +http://example.com/crawler)`, BotCategoryUnknown, "", true},
+		{iPhoneUserAgent, "", "", false},
+		{desktopUserAgent, "", "", false},
+	}
+	for _, test := range tests {
+		category, name, ok := New(newTestRequest(test.userAgent, nil), nil).ClassifyBot()
+		if test.ok != ok || test.category != category || ("" != test.name && test.name != name) {
+			t.Errorf("For %s, expected %s %q %t got %s %q %t", test.userAgent, test.category, test.name, test.ok, category, name, ok)
+		}
+	}
+}
+
+func TestBotPolicyHandler(t *testing.T) {
+	var logs bytes.Buffer
+	p := NewBotPolicy(nil)
+	p.TarpitDelay = time.Millisecond
+	p.Logger = log.New(&logs, "", 0)
+	p.Lite = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("lite")) })
+	p.Rules = append([]BotRule{{Name: "SemrushBot", Action: BotTarpit}}, p.Rules...)
+	h := p.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("full")) }))
+
+	tests := []struct {
+		userAgent string
+		code      int
+		body      string
+	}{
+		{iPhoneUserAgent, http.StatusOK, "full"},
+		{`Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`, http.StatusOK, "full"},
+		{`Twitterbot/1.0`, http.StatusOK, "lite"},
+		{`curl/8.4.0`, http.StatusForbidden, ""},
+		{`Mozilla/5.0 (compatible; SemrushBot/7~bl; +http://www.semrush.com/bot.html)`, http.StatusOK, "full"},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newTestRequest(test.userAgent, nil))
+		if test.code != rec.Code || ("" != test.body && test.body != rec.Body.String()) {
+			t.Errorf("For %s, expected %d %q got %d %q", test.userAgent, test.code, test.body, rec.Code, rec.Body.String())
+		}
+	}
+
+	stats := p.Stats()
+	if 1 != stats["search.allow"] || 1 != stats["scraper.block"] || 1 != stats["scraper.tarpit"] {
+		t.Errorf("Unexpected stats: %v", stats)
+	}
+	if !strings.Contains(logs.String(), "scraper bot curl GET /: block") {
+		t.Errorf("Decision was not logged: %s", logs.String())
+	}
+}
+
+func TestBotPolicyRateLimit(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	p := NewBotPolicy(nil)
+	p.Rate = 1
+	p.Burst = 2
+	p.now = func() time.Time { return now }
+	h := p.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	crawler := `Mozilla/5.0 (compatible; ExampleCrawler/1.0; +http://example.com/crawler)`
+
+	for i, expected := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newTestRequest(crawler, nil))
+		if expected != rec.Code {
+			t.Errorf("Request %d: expected %d got %d", i, expected, rec.Code)
+		}
+		if http.StatusTooManyRequests == rec.Code && "1" != rec.Header().Get("Retry-After") {
+			t.Errorf("Expected Retry-After: 1 got %q", rec.Header().Get("Retry-After"))
+		}
+	}
+
+	now = now.Add(time.Second)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newTestRequest(crawler, nil))
+	if http.StatusOK != rec.Code {
+		t.Errorf("Bucket was not refilled: %d", rec.Code)
+	}
+
+	// Renaming an unknown crawler does not give it a new bucket.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newTestRequest(`Mozilla/5.0 (compatible; OtherCrawler/2.0; +http://example.com/crawler)`, nil))
+	if http.StatusTooManyRequests != rec.Code {
+		t.Errorf("Expected a renamed crawler to be rate limited got %d", rec.Code)
+	}
+	if 1 != len(p.buckets) {
+		t.Errorf("Expected a single bucket got %d", len(p.buckets))
+	}
+}
+
+func TestLoadBotPolicy(t *testing.T) {
+	p, err := LoadBotPolicy(strings.NewReader(`{
+		"tarpitDelay": "10s",
+		"rate": 0.5,
+		"rules": [{"name": "ahrefsbot", "action": "tarpit"}, {"category": "scraper", "action": "allow"}]
+	}`), nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 10*time.Second != p.TarpitDelay || 0.5 != p.Rate || 5 != p.Burst {
+		t.Errorf("Unexpected policy: %+v", p)
+	}
+	md := New(newTestRequest(`Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)`, nil), nil)
+	if decision := p.Decide(md); BotTarpit != decision.Action {
+		t.Errorf("Expected tarpit got %+v", decision)
+	}
+
+	for _, invalid := range []string{`{"rules": [{"action": "ban"}]}`, `{"rules": [{"category": "seo", "action": "block"}]}`, `{"tarpitDelay": "soon"}`, `[`} {
+		if _, err := LoadBotPolicy(strings.NewReader(invalid), nil); nil == err {
+			t.Errorf("Expected an error for %s", invalid)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package mobiledetect
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+)
+
+//go:embed data/carriers.json
+var carriersJSON []byte
+
+// CarrierHeader is an entry of a CarrierTable: a header set by an operator gateway.
+type CarrierHeader struct {
+	// Header is the name of the header.
+	Header string `json:"header"`
+	// Value, when set, is a regular expression the header value must match.
+	Value string `json:"value,omitempty"`
+	// Carrier is the operator the header identifies, empty for headers set by several operators.
+	Carrier string `json:"carrier,omitempty"`
+	// Gateway is the kind of gateway setting the header: "wap", "openwave" or "proxy".
+	Gateway string `json:"gateway,omitempty"`
+	// Subscriber marks the headers carrying the subscriber identity, e.g. the phone number.
+	Subscriber bool `json:"subscriber,omitempty"`
+
+	value *regexp.Regexp
+}
+
+// CarrierTable lists the operator headers, the first matching entries giving the carrier and the gateway.
+type CarrierTable []CarrierHeader
+
+// Carrier identifies the operator gateway a request went through. The header values are
+// never exposed, subscriber identities being personal data.
+type Carrier struct {
+	// Name is the operator, empty when the headers don't tell.
+	Name string `json:"name"`
+	// Gateway is the kind of gateway, see CarrierHeader.
+	Gateway string `json:"gateway"`
+	// Headers are the names of the operator headers found.
+	Headers []string `json:"headers"`
+	// Subscriber reports whether the operator disclosed the subscriber identity.
+	Subscriber bool `json:"subscriber"`
+}
+
+// DefaultCarriers is the embedded carrier table. Replace it to use another one:
+//
+//	mobiledetect.DefaultCarriers, err = mobiledetect.LoadCarriersFile("carriers.json")
+var DefaultCarriers = mustLoadCarriers(carriersJSON)
+
+func mustLoadCarriers(data []byte) CarrierTable {
+	t, err := LoadCarriers(bytes.NewReader(data))
+	if nil != err {
+		panic(err)
+	}
+	return t
+}
+
+// LoadCarriers reads a carrier table from JSON, in the format of data/carriers.json.
+func LoadCarriers(r io.Reader) (CarrierTable, error) {
+	var t CarrierTable
+	if err := json.NewDecoder(r).Decode(&t); nil != err {
+		return nil, fmt.Errorf("mobiledetect: decoding carriers: %v", err)
+	}
+	for i := range t {
+		if "" == t[i].Header {
+			return nil, fmt.Errorf("mobiledetect: carrier entry %d has no header", i)
+		}
+		t[i].Header = http.CanonicalHeaderKey(t[i].Header)
+		if "" == t[i].Value {
+			continue
+		}
+		value, err := regexp.Compile(t[i].Value)
+		if nil != err {
+			return nil, fmt.Errorf("mobiledetect: carrier header %s: %v", t[i].Header, err)
+		}
+		t[i].value = value
+	}
+	return t, nil
+}
+
+// LoadCarriersFile reads a carrier table from a JSON file, see LoadCarriers.
+func LoadCarriersFile(path string) (CarrierTable, error) {
+	f, err := os.Open(path)
+	if nil != err {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadCarriers(f)
+}
+
+// Identify returns the carrier of the request md detects, and false when it holds no operator header.
+func (t CarrierTable) Identify(md *MobileDetect) (Carrier, bool) {
+	var c Carrier
+	seen := map[string]bool{}
+	for _, entry := range t {
+		value := md.header(entry.Header)
+		if "" == value {
+			continue
+		}
+		if nil != entry.value && !entry.value.MatchString(value) {
+			continue
+		}
+		if "" == c.Name {
+			c.Name = entry.Carrier
+		}
+		if "" == c.Gateway {
+			c.Gateway = entry.Gateway
+		}
+		c.Subscriber = c.Subscriber || entry.Subscriber
+		if !seen[entry.Header] {
+			seen[entry.Header] = true
+			c.Headers = append(c.Headers, entry.Header)
+		}
+	}
+	return c, len(c.Headers) > 0
+}
+
+// subscriber reports whether the named header carries the subscriber identity.
+func (t CarrierTable) subscriber(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	for _, entry := range t {
+		if entry.Subscriber && name == entry.Header {
+			return true
+		}
+	}
+	return false
+}
+
+// Carrier returns the operator gateway the request went through, under DefaultCarriers.
+func (md *MobileDetect) Carrier() (Carrier, bool) {
+	return DefaultCarriers.Identify(md)
+}
@@ -0,0 +1,56 @@
+package mobiledetect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCarrier(t *testing.T) {
+	tests := []struct {
+		headers  map[string]string
+		expected Carrier
+		ok       bool
+	}{
+		{nil, Carrier{}, false},
+		{map[string]string{"X-Orange-Id": "abc123"}, Carrier{Name: "Orange", Gateway: "wap", Headers: []string{"X-Orange-Id"}, Subscriber: true}, true},
+		{map[string]string{"X-Vodafone-3GPDPContext": "1"}, Carrier{Name: "Vodafone", Gateway: "wap", Headers: []string{"X-Vodafone-3gpdpcontext"}}, true},
+		{map[string]string{"X-Mobile-Gateway": "Verizon Wireless"}, Carrier{Name: "Verizon", Gateway: "wap", Headers: []string{"X-Mobile-Gateway"}}, true},
+		{map[string]string{"X-Mobile-Gateway": "1"}, Carrier{Gateway: "wap", Headers: []string{"X-Mobile-Gateway"}}, true},
+		{map[string]string{"X-Up-Subno": "33612345678", "X-Att-Deviceid": "SAMSUNG-SGH-A877"}, Carrier{Name: "AT&T", Gateway: "wap", Headers: []string{"X-Att-Deviceid", "X-Up-Subno"}, Subscriber: true}, true},
+		{map[string]string{"X-Msisdn": "33612345678"}, Carrier{Gateway: "wap", Headers: []string{"X-Msisdn"}, Subscriber: true}, true},
+		{map[string]string{"Via": "1.1 proxy.example.com"}, Carrier{}, false},
+	}
+	for _, test := range tests {
+		c, ok := New(newTestRequest(iPhoneUserAgent, test.headers), nil).Carrier()
+		if test.ok != ok || !reflect.DeepEqual(test.expected, c) {
+			t.Errorf("For headers %v, expected %+v %v got %+v %v", test.headers, test.expected, test.ok, c, ok)
+		}
+	}
+}
+
+func TestCarrierHTTPHeaders(t *testing.T) {
+	md := New(newTestRequest(iPhoneUserAgent, nil), nil)
+	md.SetHTTPHeaders(map[string]string{"HTTP_X_NOKIA_GATEWAY_ID": "NBG/1.0"})
+	c, ok := md.Carrier()
+	if !ok || "Nokia" != c.Name || "proxy" != c.Gateway {
+		t.Errorf("Expected the Nokia gateway from the CGI headers, got %+v %v", c, ok)
+	}
+}
+
+func TestLoadCarriers(t *testing.T) {
+	table, err := LoadCarriers(strings.NewReader(`[{"header": "x-example-operator", "value": "^acme", "carrier": "Acme", "gateway": "wap"}]`))
+	if nil != err {
+		t.Fatal(err)
+	}
+	md := New(newTestRequest(iPhoneUserAgent, map[string]string{"X-Example-Operator": "acme-1"}), nil)
+	if c, ok := table.Identify(md); !ok || "Acme" != c.Name || "X-Example-Operator" != c.Headers[0] {
+		t.Errorf("Unexpected carrier %+v %v", c, ok)
+	}
+
+	for _, invalid := range []string{`{`, `[{"carrier": "Acme"}]`, `[{"header": "X-Example", "value": "("}]`} {
+		if _, err := LoadCarriers(strings.NewReader(invalid)); nil == err {
+			t.Errorf("Expected an error loading %s", invalid)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ClientHints lists the client hints the detector reads. They are advertised
+// by AcceptCH so that supporting browsers send them on subsequent requests.
+var ClientHints = []string{
+	"Sec-CH-Viewport-Width",
+	"Sec-CH-Viewport-Height",
+	"Sec-CH-DPR",
+	"Sec-CH-Width",
+	"Sec-CH-Device-Memory",
+	"ECT",
+	"RTT",
+	"Downlink",
+	"Sec-CH-Prefers-Color-Scheme",
+	"Sec-CH-Prefers-Reduced-Motion",
+	"Sec-CH-Prefers-Reduced-Transparency",
+	"Sec-CH-Prefers-Contrast",
+	"Sec-CH-UA-Form-Factors",
+	"Sec-CH-UA-Platform-Version",
+}
+
+// AcceptCH advertises the given client hints, or ClientHints when none are given,
+// in the Accept-CH response header. It must be called before the header is written.
+func AcceptCH(w http.ResponseWriter, hints ...string) {
+	if len(hints) == 0 {
+		hints = ClientHints
+	}
+	w.Header().Set("Accept-CH", strings.Join(hints, ", "))
+}
+
+// AcceptCHHandler advertises ClientHints on every response served by h.
+func AcceptCHHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		AcceptCH(w)
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,27 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAcceptCHHandler(t *testing.T) {
+	h := AcceptCHHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newTestRequest(iPhoneUserAgent, nil))
+
+	acceptCH := rec.Header().Get("Accept-CH")
+	for _, hint := range []string{"Sec-CH-Viewport-Width", "Sec-CH-Device-Memory", "Sec-CH-Prefers-Color-Scheme", "Sec-CH-Prefers-Contrast"} {
+		if !strings.Contains(acceptCH, hint) {
+			t.Errorf("Accept-CH %q does not advertise %s", acceptCH, hint)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	AcceptCH(rec, "Sec-CH-DPR", "ECT")
+	if "Sec-CH-DPR, ECT" != rec.Header().Get("Accept-CH") {
+		t.Errorf("Unexpected Accept-CH %q", rec.Header().Get("Accept-CH"))
+	}
+}
@@ -0,0 +1,91 @@
+package mobiledetect
+
+import (
+	"strings"
+)
+
+// Effective connection types reported by the ECT client hint.
+const (
+	ECTSlow2G = "slow-2g"
+	ECT2G     = "2g"
+	ECT3G     = "3g"
+	ECT4G     = "4g"
+)
+
+// Constraints holds the network quality and device memory hints of a request,
+// along with the markers of low-end Android builds.
+type Constraints struct {
+	// SaveData is set when the client asked for reduced data usage (Save-Data: on).
+	SaveData bool
+	// ECT is the effective connection type, one of the ECT constants, or empty.
+	ECT string
+	// RTT is the estimated round trip time in milliseconds, zero when unknown.
+	RTT int
+	// Downlink is the estimated bandwidth in megabits per second, zero when unknown.
+	Downlink float64
+	// DeviceMemory is the approximate amount of RAM in gigabytes, zero when unknown.
+	DeviceMemory float64
+	// AndroidGo is set for Android (Go edition) devices.
+	AndroidGo bool
+}
+
+// LiteThresholds are the limits under which a lite experience is recommended.
+// Zero values disable the corresponding check.
+type LiteThresholds struct {
+	// SlowECT lists the effective connection types considered slow.
+	SlowECT []string
+	// MinRTT is the round trip time, in milliseconds, from which the network is considered slow.
+	MinRTT int
+	// MaxDownlink is the bandwidth, in megabits per second, up to which the network is considered slow.
+	MaxDownlink float64
+	// MaxDeviceMemory is the amount of RAM, in gigabytes, up to which the device is considered low-end.
+	MaxDeviceMemory float64
+}
+
+// DefaultLiteThresholds are the thresholds used by LiteRecommended.
+var DefaultLiteThresholds = LiteThresholds{
+	SlowECT:         []string{ECTSlow2G, ECT2G},
+	MinRTT:          1000,
+	MaxDownlink:     0.5,
+	MaxDeviceMemory: 1,
+}
+
+// Constraints returns the Save-Data, network quality and device memory hints of the request.
+func (md *MobileDetect) Constraints() Constraints {
+	return Constraints{
+		SaveData:     strings.EqualFold(strings.TrimSpace(md.header("Save-Data")), "on"),
+		ECT:          hintToken(md.header("ECT")),
+		RTT:          int(hintNumber(md.header("RTT"))),
+		Downlink:     hintNumber(md.header("Downlink")),
+		DeviceMemory: hintNumber(md.header("Sec-CH-Device-Memory"), md.header("Device-Memory")),
+		AndroidGo:    md.match(`Android Go|Go edition`),
+	}
+}
+
+// LiteRecommended reports whether a lightweight page should be served under DefaultLiteThresholds.
+func (md *MobileDetect) LiteRecommended() bool {
+	return md.Constraints().LiteRecommended(DefaultLiteThresholds)
+}
+
+// LiteRecommended reports whether the constraints call for a lightweight page: the client
+// asked to save data, runs Android Go, or falls under any of the given thresholds.
+func (c Constraints) LiteRecommended(t LiteThresholds) bool {
+	if c.SaveData || c.AndroidGo {
+		return true
+	}
+	for _, ect := range t.SlowECT {
+		if "" != c.ECT && c.ECT == ect {
+			return true
+		}
+	}
+	if t.MinRTT > 0 && c.RTT >= t.MinRTT {
+		return true
+	}
+	if t.MaxDownlink > 0 && c.Downlink > 0 && c.Downlink <= t.MaxDownlink {
+		return true
+	}
+	if t.MaxDeviceMemory > 0 && c.DeviceMemory > 0 && c.DeviceMemory <= t.MaxDeviceMemory {
+		return true
+	}
+	return false
+}
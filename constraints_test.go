@@ -0,0 +1,52 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestConstraints(t *testing.T) {
+	androidGo := `Mozilla/5.0 (Linux; Android 8.1.0 (Go edition); Nokia 1 Build/OPM1.171019.026) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.99 Mobile Safari/537.36`
+	tests := []struct {
+		userAgent string
+		headers   map[string]string
+		expected  Constraints
+		lite      bool
+	}{
+		{desktopUserAgent, nil, Constraints{}, false},
+		{iPhoneUserAgent, map[string]string{"Save-Data": "on"}, Constraints{SaveData: true}, true},
+		{iPhoneUserAgent, map[string]string{"ECT": "2g", "RTT": "1800", "Downlink": "0.25"},
+			Constraints{ECT: ECT2G, RTT: 1800, Downlink: 0.25}, true},
+		{iPhoneUserAgent, map[string]string{"ECT": "4g", "RTT": "50", "Downlink": "10"},
+			Constraints{ECT: ECT4G, RTT: 50, Downlink: 10}, false},
+		{iPhoneUserAgent, map[string]string{"Sec-CH-Device-Memory": "0.5", "Device-Memory": "8"}, Constraints{DeviceMemory: 0.5}, true},
+		{iPhoneUserAgent, map[string]string{"Device-Memory": "4"}, Constraints{DeviceMemory: 4}, false},
+		{androidGo, nil, Constraints{AndroidGo: true}, true},
+	}
+
+	for _, test := range tests {
+		detect := New(newTestRequest(test.userAgent, test.headers), nil)
+		got := detect.Constraints()
+		if test.expected != got {
+			t.Errorf("For headers %v, expected %+v got %+v", test.headers, test.expected, got)
+		}
+		if test.lite != detect.LiteRecommended() {
+			t.Errorf("For headers %v, expected lite recommendation %t", test.headers, test.lite)
+		}
+	}
+}
+
+func TestConstraintsLiteThresholds(t *testing.T) {
+	c := Constraints{ECT: ECT3G, RTT: 400, Downlink: 1.5, DeviceMemory: 2}
+	if c.LiteRecommended(DefaultLiteThresholds) {
+		t.Errorf("%+v should not be lite under the default thresholds", c)
+	}
+	if !c.LiteRecommended(LiteThresholds{SlowECT: []string{ECT3G}}) {
+		t.Errorf("%+v should be lite when 3g is slow", c)
+	}
+	if !c.LiteRecommended(LiteThresholds{MaxDeviceMemory: 2}) {
+		t.Errorf("%+v should be lite with 2GB of memory", c)
+	}
+	if c.LiteRecommended(LiteThresholds{}) {
+		t.Errorf("%+v should not be lite without thresholds", c)
+	}
+}
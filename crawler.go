@@ -0,0 +1,291 @@
+package mobiledetect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CrawlerVerdict is the outcome of a crawler verification.
+type CrawlerVerdict int
+
+const (
+	// CrawlerUnverifiable means the crawler can't be checked: unknown to the verifier, or DNS failing.
+	CrawlerUnverifiable CrawlerVerdict = iota
+	// CrawlerVerified means the request comes from the network of the crawler it claims to be.
+	CrawlerVerified
+	// CrawlerSpoofed means the request claims to be a crawler it does not come from.
+	CrawlerSpoofed
+)
+
+func (v CrawlerVerdict) String() string {
+	switch v {
+	case CrawlerVerified:
+		return "verified"
+	case CrawlerSpoofed:
+		return "spoofed"
+	}
+	return "unverifiable"
+}
+
+// CrawlerDomains are the domains the reverse DNS of crawlers belongs to, by crawler name as
+// returned by ClassifyBot. Shared hosting domains such as googleusercontent.com, whose reverse
+// DNS any customer VM gets, must not be listed.
+var CrawlerDomains = map[string][]string{
+	"Googlebot":   {"googlebot.com", "google.com"},
+	"bingbot":     {"search.msn.com"},
+	"Applebot":    {"applebot.apple.com"},
+	"YandexBot":   {"yandex.ru", "yandex.net", "yandex.com"},
+	"Baiduspider": {"baidu.com", "baidu.jp"},
+}
+
+// Resolver looks up DNS records, *net.Resolver implements it.
+type Resolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// CrawlerVerifier checks that the crawlers are who they claim to be, from their published
+// IP ranges or through forward-confirmed reverse DNS: the IP must resolve to a host of the
+// crawler domains, which must resolve back to the IP. Verdicts are cached.
+type CrawlerVerifier struct {
+	// Resolver does the DNS lookups, net.DefaultResolver when nil.
+	Resolver Resolver
+	// Domains replaces CrawlerDomains when set.
+	Domains map[string][]string
+	// TTL is how long verified and spoofed verdicts are cached.
+	TTL time.Duration
+	// UnverifiableTTL is how long unverifiable verdicts are cached, such as DNS failures.
+	UnverifiableTTL time.Duration
+	// MaxEntries caps the cached verdicts. When full, the expired verdicts are dropped,
+	// then a tenth of the others. DefaultCrawlerCacheSize when zero.
+	MaxEntries int
+
+	rules  *rules
+	now    func() time.Time
+	mu     sync.Mutex
+	ranges map[string][]*net.IPNet
+	cache  map[string]crawlerVerdictEntry
+}
+
+type crawlerVerdictEntry struct {
+	verdict CrawlerVerdict
+	expires time.Time
+}
+
+// DefaultCrawlerCacheSize is the number of verdicts a CrawlerVerifier caches by default.
+const DefaultCrawlerCacheSize = 10000
+
+// NewCrawlerVerifier creates a CrawlerVerifier caching verdicts for a day, and DNS failures for a minute.
+func NewCrawlerVerifier(rules *rules, resolver Resolver) *CrawlerVerifier {
+	return &CrawlerVerifier{
+		Resolver:        resolver,
+		TTL:             24 * time.Hour,
+		UnverifiableTTL: time.Minute,
+		rules:           rules,
+	}
+}
+
+// LoadIPRanges adds the IP ranges of the named crawler, in the JSON format published by Google and Bing:
+//
+//	{"prefixes": [{"ipv4Prefix": "66.249.64.0/27"}, {"ipv6Prefix": "2001:4860:4801:10::/64"}]}
+func (v *CrawlerVerifier) LoadIPRanges(name string, r io.Reader) error {
+	var file struct {
+		Prefixes []struct {
+			IPv4Prefix string `json:"ipv4Prefix"`
+			IPv6Prefix string `json:"ipv6Prefix"`
+		} `json:"prefixes"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); nil != err {
+		return fmt.Errorf("mobiledetect: decoding %s IP ranges: %v", name, err)
+	}
+
+	var ranges []*net.IPNet
+	for _, prefix := range file.Prefixes {
+		for _, cidr := range []string{prefix.IPv4Prefix, prefix.IPv6Prefix} {
+			if "" == cidr {
+				continue
+			}
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if nil != err {
+				return fmt.Errorf("mobiledetect: %s IP ranges: %v", name, err)
+			}
+			ranges = append(ranges, ipNet)
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if nil == v.ranges {
+		v.ranges = make(map[string][]*net.IPNet)
+	}
+	v.ranges[name] = append(v.ranges[name], ranges...)
+	v.cache = nil
+	return nil
+}
+
+// LoadIPRangesFile adds the IP ranges of the named crawler from a file, see LoadIPRanges.
+func (v *CrawlerVerifier) LoadIPRangesFile(name, path string) error {
+	f, err := os.Open(path)
+	if nil != err {
+		return err
+	}
+	defer f.Close()
+	return v.LoadIPRanges(name, f)
+}
+
+// VerifyRequest verifies the crawler the request claims to be, see ClassifyBot, against the
+// address it comes from. The remote address is used as is: behind a proxy, set it from
+// the forwarded headers beforehand. Requests not claiming to be a crawler are unverifiable.
+func (v *CrawlerVerifier) VerifyRequest(r *http.Request) (CrawlerVerdict, string) {
+	_, name, ok := detect(r, v.rules).ClassifyBot()
+	if !ok {
+		return CrawlerUnverifiable, ""
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if nil != err {
+		host = r.RemoteAddr
+	}
+	return v.Verify(r.Context(), name, host), name
+}
+
+// Verify tells whether ip belongs to the named crawler.
+func (v *CrawlerVerifier) Verify(ctx context.Context, name, ip string) CrawlerVerdict {
+	addr := net.ParseIP(ip)
+	if nil == addr {
+		return CrawlerUnverifiable
+	}
+	key := name + "|" + addr.String()
+	now := v.clock()
+
+	v.mu.Lock()
+	entry, ok := v.cache[key]
+	ranges := v.ranges[name]
+	v.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.verdict
+	}
+
+	verdict := v.verify(ctx, name, addr, ranges)
+	ttl := v.TTL
+	if CrawlerUnverifiable == verdict {
+		ttl = v.UnverifiableTTL
+	}
+	if ttl > 0 {
+		v.mu.Lock()
+		if nil == v.cache {
+			v.cache = make(map[string]crawlerVerdictEntry)
+		}
+		if _, ok := v.cache[key]; !ok {
+			v.evict(now)
+		}
+		v.cache[key] = crawlerVerdictEntry{verdict: verdict, expires: now.Add(ttl)}
+		v.mu.Unlock()
+	}
+	return verdict
+}
+
+// evict makes room for a verdict in a full cache, v.mu must be held. It drops a batch of
+// verdicts at once so that the cache is only scanned once every tenth of its size.
+func (v *CrawlerVerifier) evict(now time.Time) {
+	max := v.MaxEntries
+	if max <= 0 {
+		max = DefaultCrawlerCacheSize
+	}
+	if len(v.cache) < max {
+		return
+	}
+	for key, entry := range v.cache {
+		if !now.Before(entry.expires) {
+			delete(v.cache, key)
+		}
+	}
+	keep := max - max/10 - 1
+	for key := range v.cache {
+		if len(v.cache) <= keep {
+			break
+		}
+		delete(v.cache, key)
+	}
+}
+
+func (v *CrawlerVerifier) verify(ctx context.Context, name string, ip net.IP, ranges []*net.IPNet) CrawlerVerdict {
+	for _, ipNet := range ranges {
+		if ipNet.Contains(ip) {
+			return CrawlerVerified
+		}
+	}
+
+	domains := v.Domains
+	if nil == domains {
+		domains = CrawlerDomains
+	}
+	suffixes, ok := domains[name]
+	if !ok {
+		if len(ranges) > 0 {
+			return CrawlerSpoofed
+		}
+		return CrawlerUnverifiable
+	}
+
+	resolver := v.Resolver
+	if nil == resolver {
+		resolver = net.DefaultResolver
+	}
+	hosts, err := resolver.LookupAddr(ctx, ip.String())
+	if nil != err {
+		if isNotFound(err) {
+			return CrawlerSpoofed
+		}
+		return CrawlerUnverifiable
+	}
+
+	for _, host := range hosts {
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if !inDomains(host, suffixes) {
+			continue
+		}
+		addrs, err := resolver.LookupIPAddr(ctx, host)
+		if nil != err {
+			if isNotFound(err) {
+				continue
+			}
+			return CrawlerUnverifiable
+		}
+		for _, addr := range addrs {
+			if addr.IP.Equal(ip) {
+				return CrawlerVerified
+			}
+		}
+	}
+	return CrawlerSpoofed
+}
+
+func inDomains(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+func (v *CrawlerVerifier) clock() time.Time {
+	if nil != v.now {
+		return v.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,186 @@
+package mobiledetect
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeResolver struct {
+	ptr     map[string][]string
+	a       map[string][]string
+	lookups int
+}
+
+func (f *fakeResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	f.lookups++
+	if "203.0.113.9" == addr {
+		return nil, &net.DNSError{Err: "timeout", Name: addr, IsTimeout: true}
+	}
+	hosts, ok := f.ptr[addr]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+	}
+	return hosts, nil
+}
+
+func (f *fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := f.a[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	var addrs []net.IPAddr
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func newFakeResolver() *fakeResolver {
+	return &fakeResolver{
+		ptr: map[string][]string{
+			"66.249.66.1":  {"crawl-66-249-66-1.googlebot.com."},
+			"198.51.100.7": {"crawl-66-249-66-1.googlebot.com.evil.example."},
+			"198.51.100.8": {"fake.googlebot.com."},
+			"35.190.0.1":   {"1.0.190.35.bc.googleusercontent.com."},
+		},
+		a: map[string][]string{
+			"crawl-66-249-66-1.googlebot.com":     {"66.249.66.1"},
+			"fake.googlebot.com":                  {"66.249.66.2"},
+			"1.0.190.35.bc.googleusercontent.com": {"35.190.0.1"},
+		},
+	}
+}
+
+func TestCrawlerVerifierReverseDNS(t *testing.T) {
+	v := NewCrawlerVerifier(nil, newFakeResolver())
+	tests := []struct {
+		name     string
+		ip       string
+		expected CrawlerVerdict
+	}{
+		{"Googlebot", "66.249.66.1", CrawlerVerified},
+		{"Googlebot", "198.51.100.7", CrawlerSpoofed},
+		{"Googlebot", "198.51.100.8", CrawlerSpoofed},
+		{"Googlebot", "192.0.2.1", CrawlerSpoofed},
+		{"Googlebot", "35.190.0.1", CrawlerSpoofed},
+		{"Googlebot", "203.0.113.9", CrawlerUnverifiable},
+		{"ExampleCrawler", "66.249.66.1", CrawlerUnverifiable},
+		{"Googlebot", "not an ip", CrawlerUnverifiable},
+	}
+	for _, test := range tests {
+		if verdict := v.Verify(context.Background(), test.name, test.ip); test.expected != verdict {
+			t.Errorf("For %s from %s, expected %s got %s", test.name, test.ip, test.expected, verdict)
+		}
+	}
+}
+
+func TestCrawlerVerifierCache(t *testing.T) {
+	resolver := newFakeResolver()
+	now := time.Unix(1600000000, 0)
+	v := NewCrawlerVerifier(nil, resolver)
+	v.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		v.Verify(context.Background(), "Googlebot", "66.249.66.1")
+		v.Verify(context.Background(), "Googlebot", "203.0.113.9")
+	}
+	if 2 != resolver.lookups {
+		t.Errorf("Expected 2 lookups got %d", resolver.lookups)
+	}
+
+	now = now.Add(2 * time.Minute)
+	v.Verify(context.Background(), "Googlebot", "66.249.66.1")
+	v.Verify(context.Background(), "Googlebot", "203.0.113.9")
+	if 3 != resolver.lookups {
+		t.Errorf("Expected only the unverifiable verdict to expire, got %d lookups", resolver.lookups)
+	}
+}
+
+func TestCrawlerVerifierCacheSize(t *testing.T) {
+	resolver := newFakeResolver()
+	now := time.Unix(1600000000, 0)
+	v := NewCrawlerVerifier(nil, resolver)
+	v.now = func() time.Time { return now }
+	v.MaxEntries = 20
+
+	for i := 1; i <= 20; i++ {
+		v.Verify(context.Background(), "Googlebot", "192.0.2."+strconv.Itoa(i))
+	}
+	if 20 != len(v.cache) {
+		t.Errorf("Expected 20 cached verdicts got %d", len(v.cache))
+	}
+	v.Verify(context.Background(), "Googlebot", "192.0.2.21")
+	if 18 != len(v.cache) {
+		t.Errorf("Expected a tenth of the verdicts to be evicted, got %d cached", len(v.cache))
+	}
+	for i := 22; i <= 100; i++ {
+		v.Verify(context.Background(), "Googlebot", "192.0.2."+strconv.Itoa(i))
+		if len(v.cache) > 20 {
+			t.Fatalf("The cache grew to %d verdicts", len(v.cache))
+		}
+	}
+	if _, ok := v.cache["Googlebot|192.0.2.100"]; !ok {
+		t.Error("Expected the last verdict to be cached")
+	}
+
+	v.cache = make(map[string]crawlerVerdictEntry)
+	for i := 0; i < 20; i++ {
+		expires := now
+		if i < 5 {
+			expires = now.Add(time.Hour)
+		}
+		v.cache["Googlebot|198.51.100."+strconv.Itoa(i)] = crawlerVerdictEntry{CrawlerSpoofed, expires}
+	}
+	v.Verify(context.Background(), "Googlebot", "66.249.66.1")
+	if 6 != len(v.cache) {
+		t.Errorf("Expected the expired verdicts only to be dropped, got %d cached", len(v.cache))
+	}
+}
+
+func TestCrawlerVerifierIPRanges(t *testing.T) {
+	v := NewCrawlerVerifier(nil, &fakeResolver{})
+	err := v.LoadIPRanges("bingbot", strings.NewReader(`{
+		"creationTime": "2024-01-01T00:00:00",
+		"prefixes": [{"ipv4Prefix": "157.55.39.0/24"}, {"ipv6Prefix": "2a01:111:f100::/48"}]
+	}`))
+	if nil != err {
+		t.Fatal(err)
+	}
+	v.Domains = map[string][]string{}
+
+	for ip, expected := range map[string]CrawlerVerdict{
+		"157.55.39.10":     CrawlerVerified,
+		"2a01:111:f100::1": CrawlerVerified,
+		"192.0.2.1":        CrawlerSpoofed,
+	} {
+		if verdict := v.Verify(context.Background(), "bingbot", ip); expected != verdict {
+			t.Errorf("For %s, expected %s got %s", ip, expected, verdict)
+		}
+	}
+
+	if err := v.LoadIPRanges("bingbot", strings.NewReader(`{"prefixes": [{"ipv4Prefix": "300.0.0.0/8"}]}`)); nil == err {
+		t.Error("Expected an error for an invalid prefix")
+	}
+}
+
+func TestCrawlerVerifierRequest(t *testing.T) {
+	v := NewCrawlerVerifier(nil, newFakeResolver())
+	r := newTestRequest(`Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`, nil)
+	r.RemoteAddr = "66.249.66.1:41234"
+	if verdict, name := v.VerifyRequest(r); CrawlerVerified != verdict || "Googlebot" != name {
+		t.Errorf("Expected verified Googlebot got %s %s", verdict, name)
+	}
+
+	r.RemoteAddr = "192.0.2.1:41234"
+	if verdict, _ := v.VerifyRequest(r); CrawlerSpoofed != verdict {
+		t.Errorf("Expected spoofed got %s", verdict)
+	}
+
+	if verdict, name := v.VerifyRequest(newTestRequest(iPhoneUserAgent, nil)); CrawlerUnverifiable != verdict || "" != name {
+		t.Errorf("Expected unverifiable got %s %s", verdict, name)
+	}
+}
@@ -0,0 +1,204 @@
+package mobiledetect
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Stats are the aggregate counters of the package, kept while enabled with EnableStats.
+type Stats struct {
+	// Detections counts the MobileDetect created.
+	Detections int64 `json:"detections"`
+	// ContextReuses counts the requests reusing the MobileDetect stored in their context.
+	ContextReuses int64 `json:"contextReuses"`
+	// RegexCompilations counts the rules compiled, RegexMatches the rules matched against a User-Agent.
+	// Their difference is the number of hits of the per-request regexp cache.
+	RegexCompilations int64 `json:"regexCompilations"`
+	RegexMatches      int64 `json:"regexMatches"`
+}
+
+var (
+	stats        Stats
+	statsEnabled int32
+)
+
+// EnableStats turns the counters on or off. They are off by default since counting every regexp
+// match from every request makes the goroutines contend for the same memory.
+func EnableStats(enabled bool) {
+	var flag int32
+	if enabled {
+		flag = 1
+	}
+	atomic.StoreInt32(&statsEnabled, flag)
+}
+
+// count increments the counter when the counters are on.
+func count(counter *int64) {
+	if 1 == atomic.LoadInt32(&statsEnabled) {
+		atomic.AddInt64(counter, 1)
+	}
+}
+
+// ReadStats returns the current counters, see EnableStats.
+func ReadStats() Stats {
+	return Stats{
+		Detections:        atomic.LoadInt64(&stats.Detections),
+		ContextReuses:     atomic.LoadInt64(&stats.ContextReuses),
+		RegexCompilations: atomic.LoadInt64(&stats.RegexCompilations),
+		RegexMatches:      atomic.LoadInt64(&stats.RegexMatches),
+	}
+}
+
+// debugHeaders lists the request headers the detection may consult, besides ClientHints
+// and the DefaultCarriers headers.
+var debugHeaders = []string{
+	"User-Agent",
+	"Sec-CH-UA",
+	"Sec-CH-UA-Mobile",
+	"Sec-CH-UA-Platform",
+	"Accept",
+	"X-Wap-Profile",
+	"X-Wap-Clientid",
+	"Wap-Connection",
+	"Profile",
+	"X-Operamini-Phone-Ua",
+	"X-Operamini-Features",
+	"X-Ucbrowser-Device-Ua",
+	"X-Nokia-Gateway-Id",
+	"X-Orange-Id",
+	"X-Vodafone-3gpdpcontext",
+	"X-Huawei-Userid",
+	"Ua-Os",
+	"X-Mobile-Gateway",
+	"X-Att-Deviceid",
+	"Ua-Cpu",
+	"Save-Data",
+	"Viewport-Width",
+	"DPR",
+	"Device-Memory",
+}
+
+// DebugReport is the output of DebugHandler.
+type DebugReport struct {
+	Result       Result            `json:"result"`
+	Headers      map[string]string `json:"headers"`
+	RulesVersion string            `json:"rulesVersion"`
+	RulesSource  string            `json:"rulesSource"`
+	// CompiledRules and CompiledProperties are the sizes of the regexp caches of the inspected request.
+	CompiledRules      int   `json:"compiledRules"`
+	CompiledProperties int   `json:"compiledProperties"`
+	Stats              Stats `json:"stats"`
+}
+
+// DebugHandler serves the detection result of the current request, or of the User-Agent given
+// in the ua form value, along with the consulted headers, the rules version and the counters,
+// which are only kept after EnableStats.
+// It answers with JSON when asked for with ?format=json or Accept: application/json, with HTML otherwise.
+// Like net/http/pprof it is meant to be mounted on an internal path:
+//
+//	mux.Handle("/debug/mobiledetect", mobiledetect.DebugHandler(nil))
+func DebugHandler(rules *rules) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inspected := r
+		if userAgent := r.FormValue("ua"); "" != userAgent {
+			inspected = r.Clone(r.Context())
+			inspected.Header.Set("User-Agent", userAgent)
+		}
+		md := New(inspected, rules)
+
+		report := DebugReport{
+			Result:       md.Result(),
+			Headers:      make(map[string]string),
+			RulesVersion: RulesVersion,
+			RulesSource:  RulesSource,
+		}
+		names := append(append([]string{}, debugHeaders...), ClientHints...)
+		for _, entry := range DefaultCarriers {
+			names = append(names, entry.Header)
+		}
+		for _, name := range names {
+			value := inspected.Header.Get(name)
+			if "" == value {
+				continue
+			}
+			if DefaultCarriers.subscriber(name) {
+				value = "(redacted)"
+			}
+			report.Headers[name] = value
+		}
+		report.CompiledRules = len(md.compiledRegexRules)
+		report.CompiledProperties = len(md.properties.cache)
+		report.Stats = ReadStats()
+
+		w.Header().Set("Cache-Control", "no-store")
+		if "json" == r.FormValue("format") || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			enc.Encode(report)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		debugTemplate.Execute(w, report)
+	})
+}
+
+var debugTemplate = template.Must(template.New("debug").Funcs(template.FuncMap{
+	"sortedKeys": func(m map[string]string) []string {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>mobiledetect</title></head>
+<body>
+<h1>mobiledetect</h1>
+<form method="get">
+<textarea name="ua" rows="3" cols="100">{{.Result.UserAgent}}</textarea><br>
+<input type="submit" value="Detect">
+</form>
+<h2>Result</h2>
+<table>
+<tr><th align="left">Device type</th><td>{{.Result.DeviceType}}{{if .Result.Overridden}} (overridden){{end}}{{if .Result.RequestedDesktop}} (desktop site requested by a {{.Result.ActualDeviceType}}){{end}}</td></tr>
+<tr><th align="left">Mobile</th><td>{{.Result.Mobile}}</td></tr>
+<tr><th align="left">Tablet</th><td>{{.Result.Tablet}}</td></tr>
+<tr><th align="left">Bot</th><td>{{.Result.Bot}}</td></tr>
+<tr><th align="left">Grade</th><td>{{.Result.Grade}}</td></tr>
+<tr><th align="left">Vendor</th><td>{{.Result.Vendor}}</td></tr>
+<tr><th align="left">Browser</th><td>{{.Result.Browser}} {{.Result.BrowserVersion}}</td></tr>
+<tr><th align="left">OS</th><td>{{.Result.OS}} {{.Result.OSVersion}}</td></tr>
+<tr><th align="left">Proxy browser</th><td>{{with .Result.ProxyBrowser}}{{.Vendor}}{{if .DataSaving}} data saving{{end}}{{if .LimitedJS}} limited JS{{end}}{{end}}</td></tr>
+<tr><th align="left">Carrier</th><td>{{with .Result.Carrier}}{{.Name}} {{.Gateway}}{{if .Subscriber}} (subscriber identified){{end}}{{end}}</td></tr>
+<tr><th align="left">Matched rules</th><td>{{range .Result.Rules}}{{.}} {{end}}</td></tr>
+<tr><th align="left">Versions</th><td>{{$v := .Result.Versions}}{{range sortedKeys $v}}{{.}}={{index $v .}} {{end}}</td></tr>
+<tr><th align="left">Screen</th><td>{{printf "%+v" .Result.Screen}}</td></tr>
+<tr><th align="left">Constraints</th><td>{{printf "%+v" .Result.Constraints}}</td></tr>
+<tr><th align="left">Preferences</th><td>{{printf "%+v" .Result.Preferences}}</td></tr>
+<tr><th align="left">Anomalies</th><td>{{range .Result.Anomalies}}{{.Severity}} {{.Kind}}: {{.Detail}}<br>{{end}}</td></tr>
+</table>
+<h2>Headers</h2>
+<table>
+{{$h := .Headers}}{{range sortedKeys $h}}<tr><th align="left">{{.}}</th><td>{{index $h .}}</td></tr>
+{{end}}</table>
+<h2>Rules</h2>
+<p>Version {{.RulesVersion}} from <a href="{{.RulesSource}}">{{.RulesSource}}</a></p>
+<h2>Caches</h2>
+<p>{{.CompiledRules}} rules and {{.CompiledProperties}} properties compiled for this request.</p>
+<h2>Counters</h2>
+<table>
+<tr><th align="left">Detections</th><td>{{.Stats.Detections}}</td></tr>
+<tr><th align="left">Context reuses</th><td>{{.Stats.ContextReuses}}</td></tr>
+<tr><th align="left">Regexp compilations</th><td>{{.Stats.RegexCompilations}}</td></tr>
+<tr><th align="left">Regexp matches</th><td>{{.Stats.RegexMatches}}</td></tr>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,64 @@
+package mobiledetect
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDebugHandlerJSON(t *testing.T) {
+	EnableStats(true)
+	defer EnableStats(false)
+	before := ReadStats()
+	r := httptest.NewRequest("GET", "/debug/mobiledetect?format=json&ua="+url.QueryEscape(iPadUserAgent), nil)
+	r.Header.Set("User-Agent", desktopUserAgent)
+	r.Header.Set("Sec-CH-DPR", "2")
+	r.Header.Set("X-Up-Subno", "33612345678")
+	rec := httptest.NewRecorder()
+	DebugHandler(nil).ServeHTTP(rec, r)
+
+	var report DebugReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); nil != err {
+		t.Fatalf("Invalid JSON: %v\n%s", err, rec.Body.String())
+	}
+	if DeviceTypeTablet != report.Result.DeviceType {
+		t.Errorf("Expected the pasted User-Agent to be inspected, got %s", report.Result.DeviceType)
+	}
+	if iPadUserAgent != report.Headers["User-Agent"] || "2" != report.Headers["Sec-CH-DPR"] {
+		t.Errorf("Unexpected consulted headers: %v", report.Headers)
+	}
+	if "(redacted)" != report.Headers["X-Up-Subno"] || nil == report.Result.Carrier || !report.Result.Carrier.Subscriber {
+		t.Errorf("Expected the subscriber header to be flagged and redacted, got %v", report.Headers)
+	}
+	if RulesVersion != report.RulesVersion || 0 == report.CompiledRules {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if report.Stats.Detections <= before.Detections || report.Stats.RegexMatches <= before.RegexMatches {
+		t.Errorf("Counters did not increase: %+v then %+v", before, report.Stats)
+	}
+}
+
+func TestStatsDisabled(t *testing.T) {
+	before := ReadStats()
+	New(newTestRequest(iPhoneUserAgent, nil), nil).IsMobile()
+	if after := ReadStats(); before != after {
+		t.Errorf("Counters changed while disabled: %+v then %+v", before, after)
+	}
+}
+
+func TestDebugHandlerHTML(t *testing.T) {
+	r := httptest.NewRequest("GET", "/debug/mobiledetect", nil)
+	r.Header.Set("User-Agent", `<script>alert(1)</script> iPhone`)
+	rec := httptest.NewRecorder()
+	DebugHandler(nil).ServeHTTP(rec, r)
+
+	body := rec.Body.String()
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") || !strings.Contains(body, RulesVersion) {
+		t.Errorf("Unexpected HTML output: %s", body)
+	}
+	if strings.Contains(body, "<script>") {
+		t.Error("User-Agent is not escaped")
+	}
+}
@@ -0,0 +1,68 @@
+package mobiledetect
+
+import (
+	"strings"
+)
+
+// desktopBrowserKey returns the key of the first desktop browser rule matching the User-Agent,
+// or -1 on mobile devices and unknown browsers. Brave is also recognized by its Sec-CH-UA brand.
+func (md *MobileDetect) desktopBrowserKey() int {
+	if !md.cache.browserKeyKnown {
+		md.cache.browserKey = md.detectDesktopBrowserKey()
+		md.cache.browserKeyKnown = true
+	}
+	return md.cache.browserKey
+}
+
+func (md *MobileDetect) detectDesktopBrowserKey() int {
+	if md.IsMobile() {
+		return -1
+	}
+	for i, rule := range md.rules.desktopBrowsers {
+		key := DESKTOPEDGE + i
+		if DESKTOPBRAVE == key && md.hasBrand("Brave") {
+			return key
+		}
+		if md.match(rule) {
+			return key
+		}
+	}
+	return -1
+}
+
+// hasBrand reports whether the Sec-CH-UA client hint lists the brand.
+func (md *MobileDetect) hasBrand(name string) bool {
+	for _, brand := range brandRegex.FindAllStringSubmatch(md.header("Sec-CH-UA"), -1) {
+		if strings.EqualFold(name, brand[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// WindowsRelease returns the marketing name of the Windows version: "XP", "Vista", "7", "8", "8.1",
+// "10" or "11", and an empty string on other systems. Windows 11 still sends Windows NT 10.0,
+// it is only told apart by a Sec-CH-UA-Platform-Version of 13 or more.
+func (md *MobileDetect) WindowsRelease() string {
+	if !md.IsKey(WINDOWS) {
+		return ""
+	}
+	switch nt := md.VersionKey(PropWindowsNt); nt {
+	case "5.1", "5.2":
+		return "XP"
+	case "6.0":
+		return "Vista"
+	case "6.1":
+		return "7"
+	case "6.2":
+		return "8"
+	case "6.3":
+		return "8.1"
+	case "10.0":
+		if version := hintToken(md.header("Sec-CH-UA-Platform-Version")); "" != version && compareVersions(version, "13") >= 0 {
+			return "11"
+		}
+		return "10"
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestDesktopRules(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		headers   map[string]string
+		os        string
+		browser   string
+	}{
+		{desktopUserAgent, nil, "Windows", "DesktopChrome"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.80`, nil, "Windows", "DesktopEdge"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 OPR/110.0.0.0`, nil, "Windows", "DesktopOpera"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 YaBrowser/24.4.0.0 Safari/537.36`, nil, "Windows", "DesktopYandex"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`, map[string]string{"Sec-CH-UA": `"Chromium";v="124", "Brave";v="124", "Not-A.Brand";v="99"`}, "Windows", "DesktopBrave"},
+		{`Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Vivaldi/6.7.3329.31`, nil, "Linux", "DesktopVivaldi"},
+		{`Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0`, nil, "Linux", "DesktopFirefox"},
+		{macSafariUserAgent, nil, "macOS", "DesktopSafari"},
+		{`Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`, nil, "ChromeOS", "DesktopChrome"},
+		{`Mozilla/5.0 (X11; FreeBSD amd64; rv:124.0) Gecko/20100101 Firefox/124.0`, nil, "FreeBSD", "DesktopFirefox"},
+	}
+	desktopOS := []string{"Windows", "macOS", "Linux", "ChromeOS", "FreeBSD"}
+	desktopBrowsers := []string{"DesktopEdge", "DesktopOpera", "DesktopVivaldi", "DesktopYandex", "DesktopBrave", "DesktopChrome", "DesktopFirefox", "DesktopSafari"}
+	for _, test := range tests {
+		md := New(newTestRequest(test.userAgent, test.headers), nil)
+		if md.IsMobile() {
+			t.Errorf("For userAgent %s, expected not to be mobile", test.userAgent)
+		}
+		for _, name := range desktopOS {
+			if expected := test.os == name; expected != md.Is(name) {
+				t.Errorf("For userAgent %s, expected Is(%q) to be %v", test.userAgent, name, expected)
+			}
+		}
+		for _, name := range desktopBrowsers {
+			if expected := test.browser == name; expected != md.Is(name) {
+				t.Errorf("For userAgent %s, expected Is(%q) to be %v", test.userAgent, name, expected)
+			}
+		}
+	}
+}
+
+func TestDesktopBrowserNames(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		headers   map[string]string
+		names     map[string]bool
+	}{
+		{desktopUserAgent, nil, map[string]bool{"Chrome": false, "DesktopChrome": true, "Brave": false}},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`, map[string]string{"Sec-CH-UA": `"Chromium";v="124", "Brave";v="124", "Not-A.Brand";v="99"`}, map[string]bool{"Brave": true, "Chrome": false}},
+		{`Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Vivaldi/6.7.3329.31`, nil, map[string]bool{"Vivaldi": true, "Yandex": false}},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 YaBrowser/24.4.0.0 Safari/537.36`, nil, map[string]bool{"Yandex": true}},
+		{`Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0`, nil, map[string]bool{"Firefox": false, "DesktopFirefox": true}},
+		{`Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36`, nil, map[string]bool{"Chrome": true, "DesktopChrome": false}},
+	}
+	for _, test := range tests {
+		md := New(newTestRequest(test.userAgent, test.headers), nil)
+		for name, expected := range test.names {
+			if expected != md.Is(name) {
+				t.Errorf("For userAgent %s, expected Is(%q) to be %v", test.userAgent, name, expected)
+			}
+		}
+	}
+}
+
+func TestDesktopRulesOnMobile(t *testing.T) {
+	md := New(newTestRequest(iPhoneUserAgent, nil), nil)
+	for _, name := range []string{"macOS", "Windows", "DesktopSafari", "DesktopChrome"} {
+		if md.Is(name) {
+			t.Errorf("Expected Is(%q) to be false on an iPhone", name)
+		}
+	}
+	for _, rule := range md.MatchedRules() {
+		if "macos" == rule || "desktopsafari" == rule {
+			t.Errorf("Unexpected desktop rule %s on an iPhone", rule)
+		}
+	}
+}
+
+func TestDesktopVersions(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		property  string
+		expected  string
+	}{
+		{macSafariUserAgent, "Mac OS X", "10_15_7"},
+		{iPhoneUserAgent, "Mac OS X", ""},
+		{`Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`, "CrOS", "14541.0.0"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.80`, "Edge", "124.0.2478.80"},
+		{`Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Vivaldi/6.7.3329.31`, "Vivaldi", "6.7.3329.31"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 YaBrowser/24.4.0.0 Safari/537.36`, "YaBrowser", "24.4.0.0"},
+		// The desktop names accepted by Is.
+		{macSafariUserAgent, "macOS", "10_15_7"},
+		{`Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`, "ChromeOS", "14541.0.0"},
+		{desktopUserAgent, "Windows", "10.0"},
+		{desktopUserAgent, "DesktopChrome", "120.0.0.0"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.80`, "DesktopEdge", "124.0.2478.80"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0`, "DesktopFirefox", "125.0"},
+		{`Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 YaBrowser/24.4.0.0 Safari/537.36`, "Yandex", "24.4.0.0"},
+		{macSafariUserAgent, "DesktopSafari", "17.4"},
+	}
+	for _, test := range tests {
+		md := New(newTestRequest(test.userAgent, nil), nil)
+		if version := md.Version(test.property); test.expected != version {
+			t.Errorf("For userAgent %s, expected %s version %q got %q", test.userAgent, test.property, test.expected, version)
+		}
+	}
+	md := New(newTestRequest(macSafariUserAgent, nil), nil)
+	if version, ok := md.Result().Versions["mac os x"]; !ok || "10_15_7" != version {
+		t.Errorf("Expected the macOS version in the result, got %v", md.Result().Versions)
+	}
+}
+
+func TestWindowsRelease(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		headers   map[string]string
+		expected  string
+	}{
+		{desktopUserAgent, nil, "10"},
+		{desktopUserAgent, map[string]string{"Sec-CH-UA-Platform-Version": `"10.0.0"`}, "10"},
+		{desktopUserAgent, map[string]string{"Sec-CH-UA-Platform-Version": `"15.0.0"`}, "11"},
+		{ie11Windows7UserAgent, nil, "7"},
+		{`Mozilla/5.0 (Windows NT 6.3; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36`, nil, "8.1"},
+		{`Mozilla/5.0 (Windows NT 5.1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/49.0.2623.112 Safari/537.36`, nil, "XP"},
+		{macSafariUserAgent, nil, ""},
+	}
+	for _, test := range tests {
+		if release := New(newTestRequest(test.userAgent, test.headers), nil).WindowsRelease(); test.expected != release {
+			t.Errorf("For userAgent %s and headers %v, expected %q got %q", test.userAgent, test.headers, test.expected, release)
+		}
+	}
+}
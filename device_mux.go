@@ -0,0 +1,206 @@
+package mobiledetect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultFallbacks are the device types whose routes serve a device type which has none.
+var DefaultFallbacks = map[DeviceType][]DeviceType{
+	DeviceTypeTablet:       {DeviceTypeMobile, DeviceTypeDesktop},
+	DeviceTypeMobile:       {DeviceTypeDesktop},
+	DeviceTypeFeaturePhone: {DeviceTypeMobile, DeviceTypeDesktop},
+	DeviceTypeEReader:      {DeviceTypeTablet, DeviceTypeMobile, DeviceTypeDesktop},
+	DeviceTypeBot:          {DeviceTypeDesktop},
+}
+
+// DeviceMux is a request multiplexer with routes registered per device type.
+// A request is served by the routes of its device type, then by the routes of its fallbacks.
+// Crawlers are served by the DeviceTypeBot routes.
+type DeviceMux struct {
+	// Fallbacks replaces DefaultFallbacks when set.
+	Fallbacks map[DeviceType][]DeviceType
+	// Prefixes rewrites the path of the requests served by the routes of a device type,
+	// e.g. with {Mobile: "/m"} a phone asking for /news is served by the Mobile route for /m/news.
+	Prefixes map[DeviceType]string
+	// Override, when set, lets users force the device type.
+	Override *Override
+
+	rules    *rules
+	muxes    map[DeviceType]*http.ServeMux
+	patterns map[DeviceType]map[string]bool
+}
+
+// NewDeviceMux creates a DeviceMux using the given rules, or the default ones when nil.
+func NewDeviceMux(rules *rules) *DeviceMux {
+	return &DeviceMux{
+		rules:    rules,
+		muxes:    make(map[DeviceType]*http.ServeMux),
+		patterns: make(map[DeviceType]map[string]bool),
+	}
+}
+
+// Handle registers the handler for the given pattern and device type, see http.ServeMux.
+// Like http.ServeMux.Handle it panics when the pattern is empty or already registered.
+func (m *DeviceMux) Handle(deviceType DeviceType, pattern string, handler http.Handler) {
+	mux, ok := m.muxes[deviceType]
+	if !ok {
+		mux = http.NewServeMux()
+		m.muxes[deviceType] = mux
+		m.patterns[deviceType] = make(map[string]bool)
+	}
+	mux.Handle(pattern, handler)
+	m.patterns[deviceType][pattern] = true
+}
+
+// HandleFunc registers the handler function for the given pattern and device type.
+func (m *DeviceMux) HandleFunc(deviceType DeviceType, pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.Handle(deviceType, pattern, http.HandlerFunc(handler))
+}
+
+// ServeHTTP dispatches the request to the handler of the first device type having a route for it.
+// The MobileDetect and the device type are stored in the request context, see FromContext
+// and DeviceTypeFromContext.
+func (m *DeviceMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	md := detect(r, m.rules)
+	m.Override.Apply(w, r, md)
+
+	deviceType := md.DeviceType()
+	if !md.Overridden() && md.IsBot() {
+		deviceType = DeviceTypeBot
+	}
+
+	w.Header().Add("Vary", "User-Agent")
+	if nil != m.Override {
+		w.Header().Add("Vary", "Cookie")
+	}
+	ctx := NewContext(withDeviceType(r.Context(), deviceType), md)
+	for _, d := range fallbackChain(m.Fallbacks, deviceType) {
+		mux, ok := m.muxes[d]
+		if !ok {
+			continue
+		}
+		req := r.WithContext(ctx)
+		if prefix := m.Prefixes[d]; "" != prefix && !hasPathPrefix(r.URL.Path, prefix) {
+			u := *r.URL
+			u.Path = strings.TrimSuffix(prefix, "/") + u.Path
+			u.RawPath = ""
+			req.URL = &u
+		}
+		if h, pattern := mux.Handler(req); "" != pattern {
+			h.ServeHTTP(w, req)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// fallbackChain returns the device type followed by its fallbacks, DefaultFallbacks when nil.
+func fallbackChain(fallbacks map[DeviceType][]DeviceType, deviceType DeviceType) []DeviceType {
+	if nil == fallbacks {
+		fallbacks = DefaultFallbacks
+	}
+	return append([]DeviceType{deviceType}, fallbacks[deviceType]...)
+}
+
+// routeTable is the JSON form of a DeviceMux configuration:
+//
+//	{
+//		"fallbacks": {"Tablet": ["Mobile", "Desktop"]},
+//		"prefixes": {"Mobile": "/m"},
+//		"routes": [{"pattern": "/", "handlers": {"Desktop": "home", "Mobile": "home-mobile"}}]
+//	}
+type routeTable struct {
+	Fallbacks map[string][]string `json:"fallbacks"`
+	Prefixes  map[string]string   `json:"prefixes"`
+	Routes    []struct {
+		Pattern  string            `json:"pattern"`
+		Handlers map[string]string `json:"handlers"`
+	} `json:"routes"`
+}
+
+// LoadRoutes configures the mux from a JSON route table, handlers are referred to by their name
+// in handlers. Fallbacks and prefixes given by the table replace the current ones.
+// Nothing is changed when a pattern is empty or registered twice for a device type.
+func (m *DeviceMux) LoadRoutes(r io.Reader, handlers map[string]http.Handler) error {
+	var table routeTable
+	if err := json.NewDecoder(r).Decode(&table); nil != err {
+		return fmt.Errorf("mobiledetect: decoding route table: %v", err)
+	}
+
+	fallbacks, prefixes := m.Fallbacks, m.Prefixes
+	if nil != table.Fallbacks {
+		fallbacks = make(map[DeviceType][]DeviceType, len(table.Fallbacks))
+		for name, names := range table.Fallbacks {
+			deviceType, err := routeDeviceType(name)
+			if nil != err {
+				return err
+			}
+			for _, fallbackName := range names {
+				fallback, err := routeDeviceType(fallbackName)
+				if nil != err {
+					return err
+				}
+				fallbacks[deviceType] = append(fallbacks[deviceType], fallback)
+			}
+		}
+	}
+
+	if nil != table.Prefixes {
+		prefixes = make(map[DeviceType]string, len(table.Prefixes))
+		for name, prefix := range table.Prefixes {
+			deviceType, err := routeDeviceType(name)
+			if nil != err {
+				return err
+			}
+			prefixes[deviceType] = prefix
+		}
+	}
+
+	type registration struct {
+		deviceType DeviceType
+		pattern    string
+		handler    http.Handler
+	}
+	var registrations []registration
+	seen := make(map[DeviceType]map[string]bool)
+	for _, route := range table.Routes {
+		if "" == route.Pattern {
+			return fmt.Errorf("mobiledetect: empty route pattern")
+		}
+		for name, handlerName := range route.Handlers {
+			deviceType, err := routeDeviceType(name)
+			if nil != err {
+				return err
+			}
+			handler, ok := handlers[handlerName]
+			if !ok {
+				return fmt.Errorf("mobiledetect: unknown handler %q for route %q", handlerName, route.Pattern)
+			}
+			if nil == seen[deviceType] {
+				seen[deviceType] = make(map[string]bool)
+			}
+			if seen[deviceType][route.Pattern] || m.patterns[deviceType][route.Pattern] {
+				return fmt.Errorf("mobiledetect: duplicate route %q for %s", route.Pattern, deviceType)
+			}
+			seen[deviceType][route.Pattern] = true
+			registrations = append(registrations, registration{deviceType, route.Pattern, handler})
+		}
+	}
+	m.Fallbacks, m.Prefixes = fallbacks, prefixes
+	for _, reg := range registrations {
+		m.Handle(reg.deviceType, reg.pattern, reg.handler)
+	}
+	return nil
+}
+
+func routeDeviceType(name string) (DeviceType, error) {
+	deviceType, ok := ParseDeviceType(name)
+	if !ok {
+		return "", fmt.Errorf("mobiledetect: unknown device type %q", name)
+	}
+	return deviceType, nil
+}
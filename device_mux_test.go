@@ -0,0 +1,139 @@
+package mobiledetect
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func writeName(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, name+" "+r.URL.Path)
+	})
+}
+
+func TestDeviceMuxFallbacks(t *testing.T) {
+	googlebot := `Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`
+	m := NewDeviceMux(nil)
+	m.Prefixes = map[DeviceType]string{DeviceTypeMobile: "/m"}
+	m.Override = NewOverride(nil)
+	m.Handle(DeviceTypeDesktop, "/", writeName("desktop"))
+	m.Handle(DeviceTypeMobile, "/m/news", writeName("mobile"))
+	m.Handle(DeviceTypeBot, "/robots-only", writeName("bot"))
+
+	tests := []struct {
+		url       string
+		userAgent string
+		body      string
+	}{
+		{"/news", desktopUserAgent, "desktop /news"},
+		{"/news", iPhoneUserAgent, "mobile /m/news"},
+		{"/news", iPadUserAgent, "mobile /m/news"},
+		{"/about", iPhoneUserAgent, "desktop /about"},
+		{"/news", googlebot, "desktop /news"},
+		{"/robots-only", googlebot, "bot /robots-only"},
+		{"/news?device=desktop", iPhoneUserAgent, "desktop /news"},
+		{"/robots-only?device=bot", iPhoneUserAgent, "desktop /robots-only"},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", test.url, nil)
+		r.Header.Set("User-Agent", test.userAgent)
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, r)
+		if body := rec.Body.String(); test.body != body {
+			t.Errorf("For %s with %s, expected %q got %q", test.url, test.userAgent, test.body, body)
+		}
+		if vary := rec.Header()["Vary"]; 2 != len(vary) || "Cookie" != vary[1] {
+			t.Errorf("For %s, expected Vary User-Agent and Cookie got %v", test.url, vary)
+		}
+	}
+}
+
+func TestDeviceMuxContext(t *testing.T) {
+	m := NewDeviceMux(nil)
+	m.HandleFunc(DeviceTypeDesktop, "/", func(w http.ResponseWriter, r *http.Request) {
+		deviceType, _ := DeviceTypeFromContext(r.Context())
+		io.WriteString(w, Device(r)+" "+string(deviceType))
+	})
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, newTestRequest(`Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`, nil))
+	if body := rec.Body.String(); "Desktop Bot" != body {
+		t.Errorf("Expected %q got %q", "Desktop Bot", body)
+	}
+}
+
+func TestDeviceMuxPrefixSlash(t *testing.T) {
+	m := NewDeviceMux(nil)
+	m.Prefixes = map[DeviceType]string{DeviceTypeMobile: "/m/"}
+	m.Handle(DeviceTypeMobile, "/m/news", writeName("mobile"))
+	rec := httptest.NewRecorder()
+	r := newTestRequest(iPhoneUserAgent, nil)
+	r.URL.Path = "/news"
+	m.ServeHTTP(rec, r)
+	if body := rec.Body.String(); "mobile /m/news" != body {
+		t.Errorf("Expected %q got %q", "mobile /m/news", body)
+	}
+}
+
+func TestDeviceMuxNotFound(t *testing.T) {
+	m := NewDeviceMux(nil)
+	m.Handle(DeviceTypeMobile, "/news", writeName("mobile"))
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, newTestRequest(desktopUserAgent, nil))
+	if http.StatusNotFound != rec.Code {
+		t.Errorf("Expected 404 got %d", rec.Code)
+	}
+}
+
+func TestDeviceMuxLoadRoutes(t *testing.T) {
+	table := `{
+		"fallbacks": {"tablet": ["Desktop"]},
+		"prefixes": {"Mobile": "/m"},
+		"routes": [
+			{"pattern": "/", "handlers": {"Desktop": "home", "Mobile": "home-mobile"}}
+		]
+	}`
+	handlers := map[string]http.Handler{"home": writeName("home"), "home-mobile": writeName("home-mobile")}
+	m := NewDeviceMux(nil)
+	if err := m.LoadRoutes(strings.NewReader(table), handlers); nil != err {
+		t.Fatal(err)
+	}
+
+	for userAgent, expected := range map[string]string{
+		iPhoneUserAgent:  "home-mobile /m/",
+		iPadUserAgent:    "home /",
+		desktopUserAgent: "home /",
+	} {
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, newTestRequest(userAgent, nil))
+		if body := rec.Body.String(); expected != body {
+			t.Errorf("For %s, expected %q got %q", userAgent, expected, body)
+		}
+	}
+
+	for _, invalid := range []string{
+		`{"routes": [{"pattern": "/", "handlers": {"Watch": "home"}}]}`,
+		`{"routes": [{"pattern": "/", "handlers": {"Desktop": "missing"}}]}`,
+		`{"fallbacks": {"Tablet": ["Phablet"]}}`,
+		`{"routes": [{"pattern": "", "handlers": {"Desktop": "home"}}]}`,
+		`{"routes": [{"pattern": "/", "handlers": {"Desktop": "home"}}, {"pattern": "/", "handlers": {"Desktop": "home-mobile"}}]}`,
+		`not json`,
+	} {
+		if err := NewDeviceMux(nil).LoadRoutes(strings.NewReader(invalid), handlers); nil == err {
+			t.Errorf("Expected an error for %s", invalid)
+		}
+	}
+
+	// A pattern registered before is a duplicate too, and the table is not applied.
+	m = NewDeviceMux(nil)
+	m.Handle(DeviceTypeDesktop, "/", writeName("home"))
+	if err := m.LoadRoutes(strings.NewReader(`{"prefixes": {"Mobile": "/m"}, "routes": [{"pattern": "/", "handlers": {"Desktop": "home"}}]}`), handlers); nil == err {
+		t.Error("Expected an error for a pattern already registered")
+	}
+	if nil != m.Prefixes {
+		t.Errorf("Expected the prefixes to be left untouched, got %v", m.Prefixes)
+	}
+}
@@ -0,0 +1,46 @@
+package mobiledetect
+
+import (
+	"strings"
+)
+
+// eReaderModels maps the tablet rules matching e-reader brands to the patterns of their e-ink
+// models, the brands also selling LCD tablets: the Kindle Fire, the Kobo Arc and Vox, the Nook
+// Color and Nook Tablet, the PocketBook SURFpad.
+var eReaderModels = map[int]string{
+	// The Kindle Paperwhite, Voyage and Oasis experimental browser: Kindle/3.0+, the Fires send Silk.
+	KINDLE:           `\bKindle/[0-9]`,
+	KOBOTABLET:       `Kobo Touch`,
+	POCKETBOOKTABLET: `PocketBook`,
+	// The Nook Simple Touch and GlowLight.
+	NOOKTABLET: `BNRV[3-9][0-9]{2}|Nook Simple Touch|GlowLight`,
+}
+
+// tolinoEInkRegex matches the tolino e-ink models, on its own: the TOLINOTABLET rule only knows
+// the shine, and the LCD tolino tab.
+const tolinoEInkRegex = `tolino (shine|vision|epos|page)`
+
+// eReaderLCDRegex matches the LCD models of the brands in eReaderModels sharing their tokens.
+const eReaderLCDRegex = `Silk|Kindle Fire|SURFpad`
+
+// IsEReader reports whether the device has an e-ink screen: it sends the EInk form factor
+// client hint, or the User-Agent is one of the e-ink models of eReaderModels.
+func (md *MobileDetect) IsEReader() bool {
+	for _, formFactor := range strings.Split(md.header("Sec-CH-UA-Form-Factors"), ",") {
+		if "eink" == hintToken(formFactor) {
+			return true
+		}
+	}
+	if md.match(eReaderLCDRegex) {
+		return false
+	}
+	if md.match(tolinoEInkRegex) {
+		return true
+	}
+	for key, models := range eReaderModels {
+		if md.IsKey(key) && md.match(models) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestEReader(t *testing.T) {
+	tests := []struct {
+		userAgent  string
+		headers    map[string]string
+		deviceType DeviceType
+	}{
+		{`Mozilla/5.0 (Linux; U; en-US) AppleWebKit/528.5+ (KHTML, like Gecko, Safari/528.5+) Version/4.0 Kindle/3.0 (screen 600x800; rotate)`, nil, DeviceTypeEReader},
+		{`Mozilla/5.0 (X11; U; Linux armv7l like Android; en-us) AppleWebKit/531.2+ (KHTML, like Gecko) Version/5.0 Safari/531.2+ Kindle/3.0+`, nil, DeviceTypeEReader},
+		{`Mozilla/5.0 (Linux; U; Android 2.0; en-us;) AppleWebKit/538.1 (KHTML, like Gecko) Version/4.0 Mobile Safari/538.1 (Kobo Touch 0377/4.20.14622)`, nil, DeviceTypeEReader},
+		{`Mozilla/5.0 (Linux; U; Android 4.4.2; de-de; tolino shine 2 HD Build/KOT49H) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30`, nil, DeviceTypeEReader},
+		{`Mozilla/5.0 (Linux; Android 4.4.2; tolino vision 4 HD Build/KOT49H) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/30.0.0.0 Mobile Safari/537.36`, nil, DeviceTypeEReader},
+		{`Mozilla/5.0 (Linux; Android 8.1.0; tolino epos 2 Build/OPM1) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/61.0.3163.98 Safari/537.36`, nil, DeviceTypeEReader},
+		{`Mozilla/5.0 (Linux; U; Android 2.1; en-us; NOOK BNRV300 Build/ERD79) AppleWebKit/530.17 (KHTML, like Gecko) Version/4.0 Mobile Safari/530.17`, nil, DeviceTypeEReader},
+		{`Mozilla/5.0 (Linux; Android 4.4.4; PocketBook 631 Build/KTU84Q) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/33.0.0.0 Safari/537.36`, nil, DeviceTypeEReader},
+		{`Mozilla/5.0 (Linux; Android 9; KFMEWI) AppleWebKit/537.36 (KHTML, like Gecko) Silk/124.1.1 like Chrome/124.0.6367.82 Safari/537.36`, nil, DeviceTypeTablet},
+		{`Mozilla/5.0 (Linux; Android 4.0.4; tolino tab 8.9 Build/IMM76D) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/33.0.1750.136 Safari/537.36`, nil, DeviceTypeTablet},
+		{`Mozilla/5.0 (Linux; Android 11; Boox) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36`, map[string]string{"Sec-CH-UA-Form-Factors": `"Tablet", "EInk"`}, DeviceTypeEReader},
+		{iPadUserAgent, nil, DeviceTypeTablet},
+		{desktopUserAgent, nil, DeviceTypeDesktop},
+	}
+	for _, test := range tests {
+		md := New(newTestRequest(test.userAgent, test.headers), nil)
+		if deviceType := md.DeviceType(); test.deviceType != deviceType {
+			t.Errorf("For userAgent %s, expected %s got %s", test.userAgent, test.deviceType, deviceType)
+		}
+	}
+}
+
+func TestEReaderPreferences(t *testing.T) {
+	kindle := `Mozilla/5.0 (Linux; U; en-US) AppleWebKit/528.5+ (KHTML, like Gecko, Safari/528.5+) Version/4.0 Kindle/3.0 (screen 600x800; rotate)`
+	p := New(newTestRequest(kindle, nil), nil).Preferences()
+	if !p.PrefersReducedMotion() || !p.PrefersMoreContrast() {
+		t.Errorf("Expected e-readers to default to reduced motion and more contrast, got %+v", p)
+	}
+
+	p = New(newTestRequest(kindle, map[string]string{
+		"Sec-CH-Prefers-Reduced-Motion": "no-preference",
+		"Sec-CH-Prefers-Contrast":       "no-preference",
+	}), nil).Preferences()
+	if p.PrefersReducedMotion() || ContrastNoPreference != p.Contrast {
+		t.Errorf("Expected the hints to take precedence, got %+v", p)
+	}
+
+	if p := New(newTestRequest(iPadUserAgent, nil), nil).Preferences(); p.PrefersReducedMotion() || "" != p.Contrast {
+		t.Errorf("Expected no defaults on tablets, got %+v", p)
+	}
+}
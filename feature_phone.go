@@ -0,0 +1,74 @@
+package mobiledetect
+
+import (
+	"strings"
+)
+
+// Markup is the document type a device renders best.
+type Markup string
+
+const (
+	// MarkupHTML5 is regular HTML, which smartphones, tablets, desktops and KaiOS render.
+	MarkupHTML5 Markup = "html5"
+	// MarkupXHTMLMP is XHTML Mobile Profile, served as application/vnd.wap.xhtml+xml.
+	MarkupXHTMLMP Markup = "xhtml-mp"
+	// MarkupWML is the Wireless Markup Language of WAP 1.x browsers, served as text/vnd.wap.wml.
+	MarkupWML Markup = "wml"
+)
+
+// ContentType returns the media type documents in the markup are served with.
+func (m Markup) ContentType() string {
+	switch m {
+	case MarkupWML:
+		return "text/vnd.wap.wml"
+	case MarkupXHTMLMP:
+		return "application/vnd.wap.xhtml+xml"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// featurePhoneRegex matches the feature phone User-Agents the operating system rules miss:
+// Nokia Series 40 and Opera Mini, the proxy browser of J2ME phones.
+const featurePhoneRegex = `Series ?40|S40OviBrowser|Opera Mini`
+
+// smartphoneOperatingSystems are the rules ruling out a feature phone, e.g. Opera Mini on Android.
+var smartphoneOperatingSystems = []int{ANDROIDOS, IOS, IPADOS, WINDOWSPHONEOS, BLACKBERRYOS, SAILFISHOS}
+
+// IsFeaturePhone reports whether the device is a feature phone: KaiOS, Smart Feature OS,
+// J2ME, BREW, Symbian or Series 40, Opera Mini on any of them, or a browser accepting WAP markup.
+func (md *MobileDetect) IsFeaturePhone() bool {
+	if md.IsTablet() {
+		return false
+	}
+	for _, key := range smartphoneOperatingSystems {
+		if md.IsKey(key) {
+			return false
+		}
+	}
+	for _, key := range []int{KAIOS, SMARTFEATUREOS, JAVAOS, BREWOS, SYMBIANOS} {
+		if md.IsKey(key) {
+			return true
+		}
+	}
+	if md.match(featurePhoneRegex) {
+		return true
+	}
+	accept := strings.ToLower(md.header("Accept"))
+	return strings.Contains(accept, "text/vnd.wap.wml") || strings.Contains(accept, "application/vnd.wap.xhtml+xml")
+}
+
+// PreferredMarkup returns the markup to serve the device. Feature phones get XHTML-MP unless
+// their Accept header only lists WML; KaiOS, Smart Feature OS and Opera Mini render HTML5.
+func (md *MobileDetect) PreferredMarkup() Markup {
+	if DeviceTypeFeaturePhone != md.DeviceType() {
+		return MarkupHTML5
+	}
+	if md.IsKey(KAIOS) || md.IsKey(SMARTFEATUREOS) || md.match(`Opera Mini`) {
+		return MarkupHTML5
+	}
+	accept := strings.ToLower(md.header("Accept"))
+	if strings.Contains(accept, "text/vnd.wap.wml") && !strings.Contains(accept, "xhtml") && !strings.Contains(accept, "text/html") {
+		return MarkupWML
+	}
+	return MarkupXHTMLMP
+}
@@ -0,0 +1,73 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestFeaturePhone(t *testing.T) {
+	tests := []struct {
+		userAgent  string
+		accept     string
+		deviceType DeviceType
+		markup     Markup
+	}{
+		{`Mozilla/5.0 (Mobile; Nokia_8110_4G; rv:48.0) Gecko/48.0 Firefox/48.0 KAIOS/2.5`, "", DeviceTypeFeaturePhone, MarkupHTML5},
+		{`Mozilla/5.0 (Series30Plus; Nokia 225 4G) AppleWebKit/537.36 (KHTML, like Gecko) Mobile Safari/537.36`, "", DeviceTypeFeaturePhone, MarkupHTML5},
+		{`Nokia6300/2.0 (05.00) Profile/MIDP-2.0 Configuration/CLDC-1.1`, "application/vnd.wap.xhtml+xml,text/vnd.wap.wml,*/*", DeviceTypeFeaturePhone, MarkupXHTMLMP},
+		{`Nokia2700c-2/2.0 (09.80) Profile/MIDP-2.1 Configuration/CLDC-1.1 nokia2700c-2/UC Browser7.6.1.82/69/444 UNTRUSTED/1.0`, "", DeviceTypeFeaturePhone, MarkupXHTMLMP},
+		{`Nokia200/2.0 (11.81) Profile/MIDP-2.1 Configuration/CLDC-1.1 S40OviBrowser/2.0.2.68.14`, "", DeviceTypeFeaturePhone, MarkupXHTMLMP},
+		{`SAMSUNG-SCH-U380 BREW/1.0`, "text/vnd.wap.wml", DeviceTypeFeaturePhone, MarkupWML},
+		{`Opera/9.80 (J2ME/MIDP; Opera Mini/4.2.14912/870; U; id) Presto/2.4.15`, "", DeviceTypeFeaturePhone, MarkupHTML5},
+		{`SomeUnknownPhone/1.0 UP.Browser/6.2`, "text/vnd.wap.wml", DeviceTypeFeaturePhone, MarkupWML},
+		{`Opera/9.80 (Android; Opera Mini/36.2.2254/119.132; U; id) Presto/2.12.423 Version/12.16`, "", DeviceTypeMobile, MarkupHTML5},
+		{iPhoneUserAgent, "", DeviceTypeMobile, MarkupHTML5},
+		{iPadUserAgent, "", DeviceTypeTablet, MarkupHTML5},
+		{desktopUserAgent, "", DeviceTypeDesktop, MarkupHTML5},
+	}
+	for _, test := range tests {
+		var headers map[string]string
+		if "" != test.accept {
+			headers = map[string]string{"Accept": test.accept}
+		}
+		md := New(newTestRequest(test.userAgent, headers), nil)
+		if deviceType := md.DeviceType(); test.deviceType != deviceType {
+			t.Errorf("For userAgent %s, expected device type %s got %s", test.userAgent, test.deviceType, deviceType)
+		}
+		if markup := md.PreferredMarkup(); test.markup != markup {
+			t.Errorf("For userAgent %s, expected markup %s got %s", test.userAgent, test.markup, markup)
+		}
+	}
+}
+
+func TestMarkupContentType(t *testing.T) {
+	expected := map[Markup]string{
+		MarkupHTML5:   "text/html; charset=utf-8",
+		MarkupXHTMLMP: "application/vnd.wap.xhtml+xml",
+		MarkupWML:     "text/vnd.wap.wml",
+	}
+	for markup, contentType := range expected {
+		if actual := markup.ContentType(); contentType != actual {
+			t.Errorf("For markup %s, expected %q got %q", markup, contentType, actual)
+		}
+	}
+}
+
+func TestFeaturePhoneVariants(t *testing.T) {
+	md := New(newTestRequest(`Mozilla/5.0 (Mobile; Nokia_8110_4G; rv:48.0) Gecko/48.0 Firefox/48.0 KAIOS/2.5`, nil), nil)
+	if !md.IsMobile() {
+		t.Error("Expected a KaiOS phone to be mobile")
+	}
+	if !md.Is("kaios") {
+		t.Error("Expected the kaios rule to match")
+	}
+	expected := []DeviceType{DeviceTypeFeaturePhone, DeviceTypeMobile, DeviceTypeDesktop}
+	chain := fallbackChain(nil, md.DeviceType())
+	if len(expected) != len(chain) {
+		t.Fatalf("Expected fallbacks %v got %v", expected, chain)
+	}
+	for i := range expected {
+		if expected[i] != chain[i] {
+			t.Errorf("Expected fallbacks %v got %v", expected, chain)
+		}
+	}
+}
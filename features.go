@@ -0,0 +1,124 @@
+package mobiledetect
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/houseme/mobiledetect/ua"
+)
+
+//go:embed data/features.json
+var featuresJSON []byte
+
+// Features of the embedded table, named after their caniuse identifiers.
+const (
+	FeatureWebP                 = "webp"
+	FeatureAVIF                 = "avif"
+	FeatureESModules            = "es6-module"
+	FeatureAsyncFunctions       = "async-functions"
+	FeatureOptionalChaining     = "optional-chaining"
+	FeatureCSSGrid              = "css-grid"
+	FeatureIntersectionObserver = "intersectionobserver"
+	FeatureFetch                = "fetch"
+)
+
+// Support tells whether a browser supports a feature.
+type Support int
+
+const (
+	// SupportUnknown means the browser, its version or the feature is unknown to the table.
+	SupportUnknown Support = iota
+	// Supported .
+	Supported
+	// Unsupported .
+	Unsupported
+)
+
+func (s Support) String() string {
+	switch s {
+	case Supported:
+		return "supported"
+	case Unsupported:
+		return "unsupported"
+	}
+	return "unknown"
+}
+
+// featureNever marks the browser families never supporting a feature.
+const featureNever = "no"
+
+// FeatureTable gives, by feature then browser family, the first version supporting the feature,
+// or "no" when no version does.
+type FeatureTable map[string]map[string]string
+
+// DefaultFeatures is the embedded feature table, a caniuse-derived subset. Replace it to use another one:
+//
+//	mobiledetect.DefaultFeatures, err = mobiledetect.LoadFeaturesFile("features.json")
+var DefaultFeatures = mustLoadFeatures(featuresJSON)
+
+func mustLoadFeatures(data []byte) FeatureTable {
+	t, err := LoadFeatures(bytes.NewReader(data))
+	if nil != err {
+		panic(err)
+	}
+	return t
+}
+
+// LoadFeatures reads a feature table from JSON, in the format of data/features.json.
+// Browser families are the names returned by Browser.
+func LoadFeatures(r io.Reader) (FeatureTable, error) {
+	var t FeatureTable
+	if err := json.NewDecoder(r).Decode(&t); nil != err {
+		return nil, fmt.Errorf("mobiledetect: decoding features: %v", err)
+	}
+	for feature, families := range t {
+		for family, version := range families {
+			if featureNever != version && len(versionParts(version)) == 0 {
+				return nil, fmt.Errorf("mobiledetect: invalid version %q of %s for %s", version, family, feature)
+			}
+		}
+	}
+	return t, nil
+}
+
+// LoadFeaturesFile reads a feature table from a JSON file, see LoadFeatures.
+func LoadFeaturesFile(path string) (FeatureTable, error) {
+	f, err := os.Open(path)
+	if nil != err {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadFeatures(f)
+}
+
+// Supports tells whether the given version of the browser family supports the feature.
+func (t FeatureTable) Supports(feature, family, version string) Support {
+	first, ok := t[feature][family]
+	if !ok {
+		return SupportUnknown
+	}
+	if featureNever == first {
+		return Unsupported
+	}
+	if len(versionParts(version)) == 0 {
+		return SupportUnknown
+	}
+	if compareVersions(version, first) < 0 {
+		return Unsupported
+	}
+	return Supported
+}
+
+// Supports tells whether the browser supports the feature, under DefaultFeatures.
+// Every iOS browser being built on the system WebKit, they are looked up as Safari of the iOS version.
+func (md *MobileDetect) Supports(feature string) Support {
+	family, version := md.Browser()
+	if system, systemVersion := md.OS(); ua.IOS == system {
+		family, version = ua.Safari, systemVersion
+	}
+	return DefaultFeatures.Supports(feature, family, version)
+}
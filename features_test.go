@@ -0,0 +1,55 @@
+package mobiledetect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		feature   string
+		expected  Support
+	}{
+		{desktopUserAgent, FeatureAVIF, Supported},
+		{chromeAndroid9UserAgent, FeatureAVIF, Unsupported},
+		{chromeAndroid9UserAgent, FeatureWebP, Supported},
+		{ie11Windows7UserAgent, FeatureFetch, Unsupported},
+		{iPhoneUserAgent, FeatureWebP, Unsupported},
+		{iPhoneUserAgent, FeatureCSSGrid, Supported},
+		{chromeIOS12UserAgent, FeatureOptionalChaining, Unsupported},
+		{desktopUserAgent, "teleportation", SupportUnknown},
+		{`Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`, FeatureWebP, SupportUnknown},
+	}
+	for _, test := range tests {
+		if actual := New(newTestRequest(test.userAgent, nil), nil).Supports(test.feature); test.expected != actual {
+			t.Errorf("For %s supporting %s, expected %s got %s", test.userAgent, test.feature, test.expected, actual)
+		}
+	}
+}
+
+func TestLoadFeatures(t *testing.T) {
+	table, err := LoadFeatures(strings.NewReader(`{"view-transitions": {"Chrome": "111", "Firefox": "no"}}`))
+	if nil != err {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		family, version string
+		expected        Support
+	}{
+		{"Chrome", "111.0.5563.64", Supported},
+		{"Chrome", "110", Unsupported},
+		{"Chrome", "", SupportUnknown},
+		{"Firefox", "125.0", Unsupported},
+		{"Safari", "17.4", SupportUnknown},
+	}
+	for _, test := range tests {
+		if actual := table.Supports("view-transitions", test.family, test.version); test.expected != actual {
+			t.Errorf("For %s %s, expected %s got %s", test.family, test.version, test.expected, actual)
+		}
+	}
+
+	if _, err := LoadFeatures(strings.NewReader(`{"webp": {"Chrome": "soon"}}`)); nil == err {
+		t.Error("Expected an error for an invalid version")
+	}
+}
@@ -0,0 +1,258 @@
+package mobiledetect
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/houseme/mobiledetect/ua"
+)
+
+//go:embed data/lifecycle.json
+var lifecycleJSON []byte
+
+// Lifecycle lists the end of support dates of browser and OS versions.
+// macOS and Windows 10 are absent: their User-Agents are frozen at 10.15.7 and NT 10.0.
+// Evergreen browsers only list the last version supported on an OS, such as Chrome 109
+// on Windows 7, so their later versions are never outdated: require recent versions
+// with OutdatedPolicy.MinBrowsers.
+type Lifecycle struct {
+	Browsers map[string][]LifecycleEntry `json:"browsers"`
+	OS       map[string][]LifecycleEntry `json:"os"`
+}
+
+// LifecycleEntry tells when a version line stopped getting security updates. It also covers the older
+// versions down to the previous line listed, so a line may end before an older one, like Windows 8 and 7.
+type LifecycleEntry struct {
+	Version      string `json:"version"`
+	EndOfSupport string `json:"endOfSupport"`
+
+	end time.Time
+}
+
+// DefaultLifecycle is the embedded lifecycle table, replace it to use another one:
+//
+//	mobiledetect.DefaultLifecycle, err = mobiledetect.LoadLifecycleFile("lifecycle.json")
+var DefaultLifecycle = mustLoadLifecycle(lifecycleJSON)
+
+func mustLoadLifecycle(data []byte) *Lifecycle {
+	l, err := LoadLifecycle(bytes.NewReader(data))
+	if nil != err {
+		panic(err)
+	}
+	return l
+}
+
+// LoadLifecycle reads a lifecycle table from JSON, in the format of data/lifecycle.json.
+// Browser and OS names are the ones returned by Browser and OS, dates are formatted 2006-01-02.
+func LoadLifecycle(r io.Reader) (*Lifecycle, error) {
+	var l Lifecycle
+	if err := json.NewDecoder(r).Decode(&l); nil != err {
+		return nil, fmt.Errorf("mobiledetect: decoding lifecycle: %v", err)
+	}
+	for _, table := range []map[string][]LifecycleEntry{l.Browsers, l.OS} {
+		for name, entries := range table {
+			for i := range entries {
+				end, err := time.Parse("2006-01-02", entries[i].EndOfSupport)
+				if nil != err {
+					return nil, fmt.Errorf("mobiledetect: lifecycle of %s %s: %v", name, entries[i].Version, err)
+				}
+				entries[i].end = end
+			}
+		}
+	}
+	return &l, nil
+}
+
+// LoadLifecycleFile reads a lifecycle table from a JSON file, see LoadLifecycle.
+func LoadLifecycleFile(path string) (*Lifecycle, error) {
+	f, err := os.Open(path)
+	if nil != err {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadLifecycle(f)
+}
+
+// BrowserOutdated reports whether the browser version was out of support at the given date.
+func (l *Lifecycle) BrowserOutdated(name, version string, at time.Time) bool {
+	return outdated(l.Browsers[name], version, at)
+}
+
+// OSOutdated reports whether the OS version was out of support at the given date.
+func (l *Lifecycle) OSOutdated(name, version string, at time.Time) bool {
+	return outdated(l.OS[name], version, at)
+}
+
+// outdated reports whether the closest line the version belongs to had ended at the given date.
+func outdated(entries []LifecycleEntry, version string, at time.Time) bool {
+	if "" == version {
+		return false
+	}
+	var closest *LifecycleEntry
+	for i, entry := range entries {
+		if versionWithin(version, entry.Version) && (nil == closest || compareVersions(entry.Version, closest.Version) < 0) {
+			closest = &entries[i]
+		}
+	}
+	return nil != closest && !at.Before(closest.end)
+}
+
+// Browser returns the name and version of the browser, as found by the ua package,
+// e.g. Chrome and 120.0.6099.71. Internet Explorer 11 is reported as such rather than as Trident.
+func (md *MobileDetect) Browser() (name, version string) {
+	agent := ua.New(md.userAgent)
+	name, version = agent.Name(), agent.Version()
+	if "Trident" == name {
+		name = ua.InternetExplorer
+		if parts := versionParts(version); len(parts) > 0 {
+			version = strconv.Itoa(parts[0] + 4)
+		}
+	}
+	if "" == version {
+		version = md.Version(name)
+	}
+	return name, version
+}
+
+// OS returns the short name and version of the operating system, e.g. iOS and 13.2.3.
+// The version is taken from the properties for iOS, Android and Windows, from the ua package otherwise.
+func (md *MobileDetect) OS() (name, version string) {
+	agent := ua.New(md.userAgent)
+	name = agent.ShortOS()
+	switch name {
+	case ua.IOS:
+		version = md.VersionKey(PropIos)
+	case ua.Android:
+		version = md.VersionKey(PropAndroid)
+	case ua.Windows:
+		version = md.VersionKey(PropWindowsNt)
+	}
+	if "" == version {
+		version = agent.OSVersion()
+	}
+	return name, strings.Replace(version, "_", ".", -1)
+}
+
+// BrowserOutdated reports whether the browser was out of support at the given date, under DefaultLifecycle.
+func (md *MobileDetect) BrowserOutdated(at time.Time) bool {
+	name, version := md.Browser()
+	return DefaultLifecycle.BrowserOutdated(name, version, at)
+}
+
+// OSOutdated reports whether the OS was out of support at the given date, under DefaultLifecycle.
+func (md *MobileDetect) OSOutdated(at time.Time) bool {
+	name, version := md.OS()
+	return DefaultLifecycle.OSOutdated(name, version, at)
+}
+
+// Outdated tells why a client is considered outdated.
+type Outdated struct {
+	Browser bool `json:"browser"`
+	OS      bool `json:"os"`
+}
+
+// Any reports whether the browser or the OS is outdated.
+func (o Outdated) Any() bool {
+	return o.Browser || o.OS
+}
+
+// OutdatedPolicy is a middleware redirecting or annotating the requests of outdated clients:
+// out of support according to the lifecycle, or below the configured minimum versions.
+type OutdatedPolicy struct {
+	// Lifecycle replaces DefaultLifecycle when set.
+	Lifecycle *Lifecycle
+	// MinBrowsers and MinOS are the minimum versions by name, e.g. {"Chrome": "100"}.
+	MinBrowsers map[string]string
+	MinOS       map[string]string
+	// RedirectURL, when set, is where outdated clients are redirected to, read when Handler is called.
+	// Otherwise they are served, the handler finding the verdict with OutdatedFromContext.
+	RedirectURL string
+
+	rules *rules
+	now   func() time.Time
+}
+
+// NewOutdatedPolicy creates an OutdatedPolicy using the given rules, or the default ones when nil.
+func NewOutdatedPolicy(rules *rules) *OutdatedPolicy {
+	return &OutdatedPolicy{rules: rules}
+}
+
+// Check returns the verdict of the policy for the client.
+func (p *OutdatedPolicy) Check(md *MobileDetect) Outdated {
+	lifecycle := p.Lifecycle
+	if nil == lifecycle {
+		lifecycle = DefaultLifecycle
+	}
+	now := p.clock()
+
+	browser, browserVersion := md.Browser()
+	system, systemVersion := md.OS()
+	return Outdated{
+		Browser: lifecycle.BrowserOutdated(browser, browserVersion, now) || belowMinimum(p.MinBrowsers, browser, browserVersion),
+		OS:      lifecycle.OSOutdated(system, systemVersion, now) || belowMinimum(p.MinOS, system, systemVersion),
+	}
+}
+
+func belowMinimum(minimums map[string]string, name, version string) bool {
+	minimum, ok := minimums[name]
+	return ok && "" != version && compareVersions(version, minimum) < 0
+}
+
+type outdatedKey struct{}
+
+// OutdatedFromContext returns the verdict stored in ctx by OutdatedPolicy.Handler, if any.
+func OutdatedFromContext(ctx context.Context) (Outdated, bool) {
+	o, ok := ctx.Value(outdatedKey{}).(Outdated)
+	return o, ok
+}
+
+// Handler redirects outdated clients to RedirectURL when set, and serves the others with next.
+// Crawlers are never considered outdated. Only GET and HEAD requests are redirected, and never
+// the ones for RedirectURL itself.
+func (p *OutdatedPolicy) Handler(next http.Handler) http.Handler {
+	var target *url.URL
+	if "" != p.RedirectURL {
+		target, _ = url.Parse(p.RedirectURL)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md := detect(r, p.rules)
+		var verdict Outdated
+		if !md.IsBot() {
+			verdict = p.Check(md)
+		}
+		w.Header().Add("Vary", "User-Agent")
+
+		safe := http.MethodGet == r.Method || http.MethodHead == r.Method
+		if verdict.Any() && nil != target && safe && !isRedirectTarget(r, target) {
+			http.Redirect(w, r, p.RedirectURL, http.StatusFound)
+			return
+		}
+		ctx := context.WithValue(NewContext(r.Context(), md), outdatedKey{}, verdict)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isRedirectTarget reports whether the request is for the target, resolved against the request URL.
+func isRedirectTarget(r *http.Request, target *url.URL) bool {
+	if "" != target.Host && !strings.EqualFold(stripPort(target.Host), stripPort(r.Host)) {
+		return false
+	}
+	return r.URL.ResolveReference(target).Path == r.URL.Path
+}
+
+func (p *OutdatedPolicy) clock() time.Time {
+	if nil != p.now {
+		return p.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,145 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	ie11Windows7UserAgent   = `Mozilla/5.0 (Windows NT 6.1; Trident/7.0; rv:11.0) like Gecko`
+	chrome109UserAgent      = `Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36`
+	chromeIOS12UserAgent    = `Mozilla/5.0 (iPhone; CPU iPhone OS 12_5_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/90.0.4430.216 Mobile/15E148 Safari/604.1`
+	chromeAndroid9UserAgent = `Mozilla/5.0 (Linux; Android 9; SM-G960F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/83.0.4103.106 Mobile Safari/537.36`
+)
+
+func TestBrowserAndOS(t *testing.T) {
+	tests := []struct {
+		userAgent                              string
+		browser, browserVersion, os, osVersion string
+	}{
+		{ie11Windows7UserAgent, "Internet Explorer", "11", "Windows", "6.1"},
+		{chromeIOS12UserAgent, "Chrome", "90.0.4430.216", "iOS", "12.5.7"},
+		{chromeAndroid9UserAgent, "Chrome", "83.0.4103.106", "Android", "9"},
+		{desktopUserAgent, "Chrome", "120.0.0.0", "Windows", "10.0"},
+	}
+	for _, test := range tests {
+		md := New(newTestRequest(test.userAgent, nil), nil)
+		browser, browserVersion := md.Browser()
+		os, osVersion := md.OS()
+		if test.browser != browser || test.browserVersion != browserVersion || test.os != os || test.osVersion != osVersion {
+			t.Errorf("For %s, expected %s %s on %s %s got %s %s on %s %s", test.userAgent,
+				test.browser, test.browserVersion, test.os, test.osVersion, browser, browserVersion, os, osVersion)
+		}
+	}
+}
+
+func TestOutdated(t *testing.T) {
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		userAgent string
+		browser   bool
+		os        bool
+	}{
+		{ie11Windows7UserAgent, true, true},
+		{chrome109UserAgent, true, true},
+		{chromeIOS12UserAgent, true, true},
+		{chromeAndroid9UserAgent, true, true},
+		{iPhoneUserAgent, false, true},
+		{desktopUserAgent, false, false},
+	}
+	for _, test := range tests {
+		md := New(newTestRequest(test.userAgent, nil), nil)
+		if browser := md.BrowserOutdated(at); test.browser != browser {
+			t.Errorf("For %s, expected browser outdated %t got %t", test.userAgent, test.browser, browser)
+		}
+		if os := md.OSOutdated(at); test.os != os {
+			t.Errorf("For %s, expected OS outdated %t got %t", test.userAgent, test.os, os)
+		}
+	}
+
+	md := New(newTestRequest(ie11Windows7UserAgent, nil), nil)
+	if !md.BrowserOutdated(time.Date(2022, 6, 15, 0, 0, 0, 0, time.UTC)) || md.BrowserOutdated(time.Date(2022, 6, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Internet Explorer 11 should be outdated from 2022-06-15")
+	}
+
+	// Windows 8 ended before Windows 7.
+	at = time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	if md.OSOutdated(at) {
+		t.Error("Windows 7 should not be outdated in 2018")
+	}
+	md = New(newTestRequest(`Mozilla/5.0 (Windows NT 6.2; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/65.0.3325.181 Safari/537.36`, nil), nil)
+	if !md.OSOutdated(at) {
+		t.Error("Windows 8 should be outdated in 2018")
+	}
+}
+
+func TestLoadLifecycle(t *testing.T) {
+	l, err := LoadLifecycle(strings.NewReader(`{"browsers": {"Chrome": [{"version": "120", "endOfSupport": "2024-01-01"}]}}`))
+	if nil != err {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if !l.BrowserOutdated("Chrome", "120.0.0.0", at) || l.BrowserOutdated("Chrome", "121.0", at) || l.OSOutdated("Windows", "6.1", at) {
+		t.Errorf("Unexpected lifecycle: %+v", l)
+	}
+
+	if _, err := LoadLifecycle(strings.NewReader(`{"os": {"iOS": [{"version": "12", "endOfSupport": "soon"}]}}`)); nil == err {
+		t.Error("Expected an error for an invalid date")
+	}
+}
+
+func TestOutdatedPolicy(t *testing.T) {
+	p := NewOutdatedPolicy(nil)
+	p.MinBrowsers = map[string]string{"Firefox": "120"}
+	p.now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	var verdict Outdated
+	h := p.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verdict, _ = OutdatedFromContext(r.Context())
+	}))
+	for userAgent, expected := range map[string]Outdated{
+		chromeAndroid9UserAgent: {Browser: true, OS: true},
+		`Mozilla/5.0 (X11; Linux x86_64; rv:116.0) Gecko/20100101 Firefox/116.0`: {Browser: true},
+		`Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0`: {},
+		ie11Windows7UserAgent: {Browser: true, OS: true},
+		desktopUserAgent:      {},
+	} {
+		verdict = Outdated{}
+		h.ServeHTTP(httptest.NewRecorder(), newTestRequest(userAgent, nil))
+		if expected != verdict {
+			t.Errorf("For %s, expected %+v got %+v", userAgent, expected, verdict)
+		}
+	}
+
+	tests := []struct {
+		redirectURL string
+		method      string
+		url         string
+		location    string
+	}{
+		{"/upgrade", "GET", "/news", "/upgrade"},
+		{"/upgrade", "HEAD", "/news", "/upgrade"},
+		{"/upgrade", "POST", "/news", ""},
+		{"/upgrade", "GET", "/upgrade", ""},
+		{"/upgrade?from=old", "GET", "/upgrade?from=old", ""},
+		{"/upgrade?from=old", "GET", "/news", "/upgrade?from=old"},
+		{"upgrade", "GET", "/help/news", "/help/upgrade"},
+		{"upgrade", "GET", "/help/upgrade", ""},
+		{"http://example.com/upgrade", "GET", "http://example.com/upgrade", ""},
+		{"http://upgrade.example/upgrade", "GET", "http://example.com/upgrade", "http://upgrade.example/upgrade"},
+	}
+	for _, test := range tests {
+		p.RedirectURL = test.redirectURL
+		h := p.Handler(http.NotFoundHandler())
+		r := httptest.NewRequest(test.method, test.url, nil)
+		r.Header.Set("User-Agent", chromeAndroid9UserAgent)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if location := rec.Header().Get("Location"); test.location != location {
+			t.Errorf("For %s %s with %s, expected redirect to %q got %q", test.method, test.url, test.redirectURL, test.location, location)
+		}
+	}
+}
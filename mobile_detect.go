@@ -28,6 +28,7 @@ const (
 )
 
 // Device Vars returns the route variables for the current request, if any.
+// The device type is Mobile, Tablet or Desktop, see DeviceTypeFromContext for the others.
 func Device(r *http.Request) string {
 	if rv := r.Context().Value("Device"); rv != nil {
 		return rv.(string)
@@ -35,6 +36,87 @@ func Device(r *http.Request) string {
 	return ""
 }
 
+type deviceTypeKey struct{}
+
+// DeviceTypeFromContext returns the device type stored in ctx by HandlerMux or DeviceMux, if any,
+// feature phones and e-readers included.
+func DeviceTypeFromContext(ctx context.Context) (DeviceType, bool) {
+	deviceType, ok := ctx.Value(deviceTypeKey{}).(DeviceType)
+	return deviceType, ok
+}
+
+// withDeviceType returns a copy of ctx carrying the device type for Device and DeviceTypeFromContext.
+func withDeviceType(ctx context.Context, deviceType DeviceType) context.Context {
+	ctx = context.WithValue(ctx, "Device", string(legacyDeviceType(deviceType)))
+	return context.WithValue(ctx, deviceTypeKey{}, deviceType)
+}
+
+// legacyDeviceType folds the device type into Mobile, Tablet or Desktop, the ones known to
+// DeviceHandler and Device: e-readers are tablets, feature phones are mobiles.
+func legacyDeviceType(deviceType DeviceType) DeviceType {
+	switch deviceType {
+	case DeviceTypeTablet, DeviceTypeEReader:
+		return DeviceTypeTablet
+	case DeviceTypeMobile, DeviceTypeFeaturePhone:
+		return DeviceTypeMobile
+	}
+	return DeviceTypeDesktop
+}
+
+// DeviceType is the class of device a request has been classified as.
+type DeviceType string
+
+const (
+	// DeviceTypeDesktop .
+	DeviceTypeDesktop DeviceType = "Desktop"
+	// DeviceTypeMobile .
+	DeviceTypeMobile DeviceType = "Mobile"
+	// DeviceTypeTablet .
+	DeviceTypeTablet DeviceType = "Tablet"
+	// DeviceTypeFeaturePhone is a phone with a limited browser, see IsFeaturePhone.
+	// It is served as Mobile where no feature phone variant exists.
+	DeviceTypeFeaturePhone DeviceType = "FeaturePhone"
+	// DeviceTypeEReader is an e-ink device, see IsEReader. It is served as Tablet where no e-reader variant exists.
+	DeviceTypeEReader DeviceType = "EReader"
+	// DeviceTypeBot is the class DeviceMux serves crawlers with. DeviceType only returns it when
+	// set with SetOverride, Override refuses it.
+	DeviceTypeBot DeviceType = "Bot"
+)
+
+var deviceTypes = []DeviceType{DeviceTypeDesktop, DeviceTypeMobile, DeviceTypeTablet, DeviceTypeFeaturePhone, DeviceTypeEReader, DeviceTypeBot}
+
+// ParseDeviceType returns the device type named s, case-insensitively.
+func ParseDeviceType(s string) (DeviceType, bool) {
+	for _, deviceType := range deviceTypes {
+		if strings.EqualFold(s, string(deviceType)) {
+			return deviceType, true
+		}
+	}
+	return "", false
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying md.
+func NewContext(ctx context.Context, md *MobileDetect) context.Context {
+	return context.WithValue(ctx, contextKey{}, md)
+}
+
+// FromContext returns the MobileDetect stored in ctx by HandlerMux, if any.
+func FromContext(ctx context.Context) (*MobileDetect, bool) {
+	md, ok := ctx.Value(contextKey{}).(*MobileDetect)
+	return md, ok
+}
+
+// detect returns the MobileDetect stored in the request context, or a new one for the request.
+func detect(r *http.Request, rules *rules) *MobileDetect {
+	if md, ok := FromContext(r.Context()); ok {
+		count(&stats.ContextReuses)
+		return md
+	}
+	return New(r, rules)
+}
+
 // DeviceHandler .
 type DeviceHandler interface {
 	Mobile(w http.ResponseWriter, r *http.Request, m *MobileDetect)
@@ -44,13 +126,20 @@ type DeviceHandler interface {
 
 // Handler .
 func Handler(h DeviceHandler, rules *rules) http.Handler {
+	return OverrideHandler(h, rules, nil)
+}
+
+// OverrideHandler is Handler honoring the device type forced through o, which may be nil.
+func OverrideHandler(h DeviceHandler, rules *rules, o *Override) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		m := New(r, rules)
-		if m.IsTablet() {
+		o.Apply(w, r, m)
+		switch legacyDeviceType(m.DeviceType()) {
+		case DeviceTypeTablet:
 			h.Tablet(w, r, m)
-		} else if m.IsMobile() {
+		case DeviceTypeMobile:
 			h.Mobile(w, r, m)
-		} else {
+		default:
 			h.Desktop(w, r, m)
 		}
 	})
@@ -58,16 +147,18 @@ func Handler(h DeviceHandler, rules *rules) http.Handler {
 
 // HandlerMux .
 func HandlerMux(s *http.ServeMux, rules *rules) http.Handler {
+	return OverrideHandlerMux(s, rules, nil)
+}
+
+// OverrideHandlerMux is HandlerMux honoring the device type forced through o, which may be nil.
+// The MobileDetect and the device type are stored in the request context, see FromContext
+// and DeviceTypeFromContext.
+func OverrideHandlerMux(s *http.ServeMux, rules *rules, o *Override) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		m := New(r, rules)
-		if m.IsTablet() {
-			r = r.WithContext(context.WithValue(r.Context(), "Device", "Tablet"))
-		} else if m.IsMobile() {
-			r = r.WithContext(context.WithValue(r.Context(), "Device", "Mobile"))
-		} else {
-			r = r.WithContext(context.WithValue(r.Context(), "Device", "Desktop"))
-		}
-		s.ServeHTTP(w, r)
+		o.Apply(w, r, m)
+		ctx := withDeviceType(r.Context(), m.DeviceType())
+		s.ServeHTTP(w, r.WithContext(NewContext(ctx, m)))
 	})
 }
 
@@ -76,10 +167,23 @@ type MobileDetect struct {
 	rules              *rules
 	userAgent          string
 	httpHeaders        map[string]string
+	requestHeaders     http.Header
+	override           DeviceType
+	capabilities       *Capabilities
 	compiledRegexRules map[string]*regexp.Regexp
+	cache              detectionCache
 	*properties
 }
 
+// detectionCache keeps the detections several methods rely on, until the User-Agent or the headers change.
+type detectionCache struct {
+	deviceType      DeviceType
+	mobile          bool
+	mobileKnown     bool
+	browserKey      int
+	browserKeyKnown bool
+}
+
 // New creates the MobileDetect object
 func New(r *http.Request, rules *rules) *MobileDetect {
 	if nil == rules {
@@ -89,9 +193,11 @@ func New(r *http.Request, rules *rules) *MobileDetect {
 		rules:              rules,
 		userAgent:          r.UserAgent(),
 		httpHeaders:        httpHeaders(r),
+		requestHeaders:     r.Header,
 		compiledRegexRules: make(map[string]*regexp.Regexp, len(rules.mobileDetectionRules())),
 		properties:         newProperties(),
 	}
+	count(&stats.Detections)
 	return md
 }
 
@@ -118,6 +224,15 @@ func httpHeaders(r *http.Request) map[string]string {
 	return httpHeaders
 }
 
+// header returns the named request header. Headers given through SetHTTPHeaders
+// are looked up by their CGI style key (HTTP_SEC_CH_DPR) when the request has none.
+func (md *MobileDetect) header(name string) string {
+	if v := md.requestHeaders.Get(name); "" != v {
+		return v
+	}
+	return md.httpHeaders["HTTP_"+strings.ToUpper(strings.Replace(name, "-", "_", -1))]
+}
+
 // PreCompileRegexRules .
 func (md *MobileDetect) PreCompileRegexRules() *MobileDetect {
 	for _, ruleValue := range md.rules.mobileDetectionRules() {
@@ -129,21 +244,24 @@ func (md *MobileDetect) PreCompileRegexRules() *MobileDetect {
 // SetUserAgent .
 func (md *MobileDetect) SetUserAgent(userAgent string) *MobileDetect {
 	md.userAgent = userAgent
+	md.cache = detectionCache{}
 	return md
 }
 
 // SetHTTPHeaders .
 func (md *MobileDetect) SetHTTPHeaders(httpHeaders map[string]string) *MobileDetect {
 	md.httpHeaders = httpHeaders
+	md.cache = detectionCache{}
 	return md
 }
 
 // IsMobile is a specific case to detect only mobile browsers.
 func (md *MobileDetect) IsMobile() bool {
-	if md.CheckHTTPHeadersForMobile() {
-		return true
+	if !md.cache.mobileKnown {
+		md.cache.mobile = md.CheckHTTPHeadersForMobile() || md.matchDetectionRulesAgainstUA()
+		md.cache.mobileKnown = true
 	}
-	return md.matchDetectionRulesAgainstUA()
+	return md.cache.mobile
 }
 
 // IsTablet is a specific case of detect only tablet browsers on tablets. Do not overlap with IsTablet
@@ -156,12 +274,82 @@ func (md *MobileDetect) IsTablet() bool {
 	return false
 }
 
+// SetOverride forces the device type returned by DeviceType, an empty one restores detection.
+func (md *MobileDetect) SetOverride(deviceType DeviceType) *MobileDetect {
+	md.override = deviceType
+	return md
+}
+
+// Overridden reports whether the device type has been forced through SetOverride.
+func (md *MobileDetect) Overridden() bool {
+	return "" != md.override
+}
+
+// DeviceType classifies the request as an e-reader, a tablet, a feature phone, a mobile or a desktop,
+// in that order, smart TVs being desktops. A device type set with SetOverride takes precedence,
+// capabilities set with SetCapabilities tell the unfolded foldables from the phones. A phone or
+// a tablet asking for the desktop site is a desktop, see RequestedDesktop, its own class being
+// told by ActualDeviceType.
+func (md *MobileDetect) DeviceType() DeviceType {
+	if md.Overridden() {
+		return md.override
+	}
+	if md.RequestedDesktop() {
+		return DeviceTypeDesktop
+	}
+	detected := md.detectedDeviceType()
+	if measured := md.measuredDeviceType(detected); "" != measured {
+		return measured
+	}
+	return detected
+}
+
+// detectedDeviceType returns the device type of the User-Agent and headers, before overrides and capabilities.
+// Smart TVs get the desktop layout, although Tizen and webOS TVs match the mobile OS rules.
+func (md *MobileDetect) detectedDeviceType() DeviceType {
+	if "" == md.cache.deviceType {
+		md.cache.deviceType = md.detectDeviceType()
+	}
+	return md.cache.deviceType
+}
+
+func (md *MobileDetect) detectDeviceType() DeviceType {
+	switch {
+	case md.isTV():
+		return DeviceTypeDesktop
+	case md.IsEReader():
+		return DeviceTypeEReader
+	case md.IsTablet():
+		return DeviceTypeTablet
+	case md.IsFeaturePhone():
+		return DeviceTypeFeaturePhone
+	case md.IsMobile():
+		return DeviceTypeMobile
+	}
+	return DeviceTypeDesktop
+}
+
+// smartTVRegex matches the smart TV User-Agents the TV utility rule misses.
+const smartTVRegex = `SMART-TV|SmartTV|GoogleTV|Web0S|NetCast|BRAVIA`
+
+// isTV reports whether the User-Agent is a smart TV's.
+func (md *MobileDetect) isTV() bool {
+	return md.IsKey(TV) || md.match(smartTVRegex)
+}
+
+// IsBot reports whether the User-Agent belongs to a crawler, as listed by the bot utilities.
+func (md *MobileDetect) IsBot() bool {
+	return md.IsKey(BOT) || md.IsKey(MOBILEBOT)
+}
+
 // IsKey Is compared the detected browser with a "rule" from the existing rules list
 func (md *MobileDetect) IsKey(key int) bool {
 	return md.matchUAAgainstKey(key)
 }
 
 // Is It is recommended to use IsKey instead
+// The browser names only match mobile browsers: Is("Chrome") is false on a desktop, where
+// Is("DesktopChrome") is true. Brave, Vivaldi and Yandex are only desktop browsers.
 func (md *MobileDetect) Is(key interface{}) bool {
 	switch key.(type) {
 	case string:
@@ -212,7 +400,17 @@ func (md *MobileDetect) Version(propertyName interface{}) string {
 
 // Search for a certain key in the rules array.
 // If the key is found the try to match the corresponding regex agains the User-Agent.
+// Utilities and the desktop rules are not part of the mobile detection rules and are looked up on their own.
 func (md *MobileDetect) matchUAAgainstKey(key int) bool {
+	if key >= BOT && key-BOT < len(md.rules.utilities) {
+		return md.match(md.rules.utilities[key-BOT])
+	}
+	if key >= WINDOWS && key-WINDOWS < len(md.rules.desktopOS) {
+		return md.match(md.rules.desktopOS[key-WINDOWS])
+	}
+	if key >= DESKTOPEDGE && key-DESKTOPEDGE < len(md.rules.desktopBrowsers) {
+		return key == md.desktopBrowserKey()
+	}
 	ret := false
 	rules := md.rules.mobileDetectionRules()
 	for ruleKey, ruleValue := range rules {
@@ -250,7 +448,9 @@ func (md *MobileDetect) match(ruleValue string) bool {
 	re = md.compiledRegexRules[ruleValue]
 	if nil == re {
 		md.compiledRegexRules[ruleValue] = regexp.MustCompile(ruleValue)
+		count(&stats.RegexCompilations)
 	}
+	count(&stats.RegexMatches)
 	re = md.compiledRegexRules[ruleValue]
 	ret := re.MatchString(md.userAgent)
 	return ret
@@ -407,6 +407,41 @@ func TestPreCompileRegexRules(t *testing.T) {
 	}
 }
 
+func TestIsBot(t *testing.T) {
+	expectedResults := map[string]bool{
+		`Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`:                   true,
+		`Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)`:                    true,
+		`Mozilla/5.0 (compatible; YandexMobileBot/3.0; +http://yandex.com/bots)`:                     true,
+		`Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0`: false,
+		desktopUserAgent: false,
+	}
+	detect := New(httpRequest, nil)
+	for userAgent, expected := range expectedResults {
+		detect.SetUserAgent(userAgent)
+		if expected != detect.IsBot() || expected != detect.Is("bot") {
+			t.Errorf("For userAgent %s, expected bot %t", userAgent, expected)
+		}
+	}
+}
+
+func TestDeviceTypeSetUserAgent(t *testing.T) {
+	detect := New(httpRequest, nil)
+	expectedResults := []struct {
+		userAgent  string
+		deviceType DeviceType
+	}{
+		{`Mozilla/5.0 (iPod touch; CPU iPhone OS 7_0 like Mac OS X) AppleWebKit/537.51.1 (KHTML, like Gecko) Version/7.0 Mobile/11A4449d Safari/9537.53`, DeviceTypeMobile},
+		{desktopUserAgent, DeviceTypeDesktop},
+		{`Mozilla/5.0 (iPad; CPU OS 5_1_1 like Mac OS X; en-us) AppleWebKit/534.46.0 (KHTML, like Gecko) CriOS/21.0.1180.80 Mobile/9B206 Safari/7534.48.3`, DeviceTypeTablet},
+	}
+	for _, expected := range expectedResults {
+		detect.SetUserAgent(expected.userAgent)
+		if deviceType := detect.DeviceType(); expected.deviceType != deviceType || expected.deviceType != detect.ActualDeviceType() {
+			t.Errorf("For userAgent %s, expected %s got %s", expected.userAgent, expected.deviceType, deviceType)
+		}
+	}
+}
+
 func TestHandler(t *testing.T) {
 	expectedResults := map[string]string{
 		"mobile":  `Mozilla/5.0 (iPod touch; CPU iPhone OS 7_0 like Mac OS X) AppleWebKit/537.51.1 (KHTML, like Gecko) Version/7.0 Mobile/11A4449d Safari/9537.53`,
@@ -433,6 +468,18 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestHandlerSmartTV(t *testing.T) {
+	deviceHandler := &basicMethodsStruct{}
+	r := newTestRequest(`Mozilla/5.0 (SMART-TV; Linux; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36`, nil)
+	Handler(deviceHandler, nil).ServeHTTP(httptest.NewRecorder(), r)
+	if "desktop" != deviceHandler.handlerCalled {
+		t.Errorf("Expected smart TVs to be served as desktops, got %s", deviceHandler.handlerCalled)
+	}
+	if result := New(r, nil).Result(); result.Mobile || DeviceTypeDesktop != result.DeviceType {
+		t.Errorf("Expected a desktop result got mobile %v and %s", result.Mobile, result.DeviceType)
+	}
+}
+
 func TestHandlerMux(t *testing.T) {
 	expectedResults := map[string]string{
 		"Mobile":  `Mozilla/5.0 (iPod touch; CPU iPhone OS 7_0 like Mac OS X) AppleWebKit/537.51.1 (KHTML, like Gecko) Version/7.0 Mobile/11A4449d Safari/9537.53`,
@@ -458,6 +505,31 @@ func TestHandlerMux(t *testing.T) {
 	}
 }
 
+func TestHandlerMuxDeviceTypes(t *testing.T) {
+	tests := []struct {
+		userAgent  string
+		device     string
+		deviceType DeviceType
+	}{
+		{`Mozilla/5.0 (Mobile; Nokia_8110_4G; rv:48.0) Gecko/48.0 Firefox/48.0 KAIOS/2.5`, "Mobile", DeviceTypeFeaturePhone},
+		{`Mozilla/5.0 (Linux; U; en-US) AppleWebKit/528.5+ (KHTML, like Gecko, Safari/528.5+) Version/4.0 Kindle/3.0 (screen 600x800; rotate)`, "Tablet", DeviceTypeEReader},
+		{desktopUserAgent, "Desktop", DeviceTypeDesktop},
+	}
+	for _, test := range tests {
+		var device string
+		var deviceType DeviceType
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			device = Device(r)
+			deviceType, _ = DeviceTypeFromContext(r.Context())
+		})
+		HandlerMux(mux, nil).ServeHTTP(httptest.NewRecorder(), newTestRequest(test.userAgent, nil))
+		if test.device != device || test.deviceType != deviceType {
+			t.Errorf("For %s, expected %s and %s got %s and %s", test.userAgent, test.device, test.deviceType, device, deviceType)
+		}
+	}
+}
+
 func BenchmarkIsMobile(b *testing.B) {
 	req, _ := http.NewRequest("GET", "URL", strings.NewReader(""))
 	detect := New(req, nil)
@@ -0,0 +1,167 @@
+// Package mobiledetecttest provides device profiles and helpers for testing device-aware handlers.
+package mobiledetecttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/houseme/mobiledetect"
+)
+
+// Profile is a device as seen from the server: its User-Agent, the headers it sends
+// and the way mobiledetect classifies it.
+type Profile struct {
+	Name      string
+	UserAgent string
+	// Headers are the client hints and other headers sent along with the User-Agent.
+	Headers map[string]string
+	// DeviceType and Bot are the classification expected from mobiledetect.
+	DeviceType mobiledetect.DeviceType
+	Bot        bool
+}
+
+// Device profiles.
+var (
+	IPhone = Profile{
+		Name:       "iPhone",
+		UserAgent:  `Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1`,
+		Headers:    map[string]string{"Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+		DeviceType: mobiledetect.DeviceTypeMobile,
+	}
+	// IPadDesktopMode is an iPadOS Safari requesting the desktop site, the default since iPadOS 13:
+	// its User-Agent is the one of a Mac.
+	IPadDesktopMode = Profile{
+		Name:       "iPad desktop mode",
+		UserAgent:  `Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15`,
+		Headers:    map[string]string{"Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+		DeviceType: mobiledetect.DeviceTypeDesktop,
+	}
+	Pixel = Profile{
+		Name:      "Pixel",
+		UserAgent: `Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36`,
+		Headers: map[string]string{
+			"Sec-CH-UA":             `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`,
+			"Sec-CH-UA-Mobile":      "?1",
+			"Sec-CH-UA-Platform":    `"Android"`,
+			"Sec-CH-Viewport-Width": "412",
+			"Sec-CH-DPR":            "2.625",
+			"ECT":                   "4g",
+			"Device-Memory":         "8",
+		},
+		DeviceType: mobiledetect.DeviceTypeMobile,
+	}
+	GalaxyTab = Profile{
+		Name:      "Galaxy Tab",
+		UserAgent: `Mozilla/5.0 (Linux; Android 11; SM-T860) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`,
+		Headers: map[string]string{
+			"Sec-CH-UA":             `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`,
+			"Sec-CH-UA-Mobile":      "?0",
+			"Sec-CH-UA-Platform":    `"Android"`,
+			"Sec-CH-Viewport-Width": "800",
+			"Sec-CH-DPR":            "2",
+		},
+		DeviceType: mobiledetect.DeviceTypeTablet,
+	}
+	KindleFire = Profile{
+		Name:       "Kindle Fire",
+		UserAgent:  `Mozilla/5.0 (Linux; Android 9; KFMEWI) AppleWebKit/537.36 (KHTML, like Gecko) Silk/124.1.1 like Chrome/124.0.6367.82 Safari/537.36`,
+		DeviceType: mobiledetect.DeviceTypeTablet,
+	}
+	KindlePaperwhite = Profile{
+		Name:       "Kindle Paperwhite",
+		UserAgent:  `Mozilla/5.0 (Linux; U; en-US) AppleWebKit/528.5+ (KHTML, like Gecko, Safari/528.5+) Version/4.0 Kindle/3.0 (screen 600x800; rotate)`,
+		DeviceType: mobiledetect.DeviceTypeEReader,
+	}
+	FeaturePhone = Profile{
+		Name:      "Feature phone",
+		UserAgent: `Nokia6300/2.0 (05.00) Profile/MIDP-2.0 Configuration/CLDC-1.1`,
+		Headers: map[string]string{
+			"Accept":        "text/html,application/xhtml+xml,application/vnd.wap.xhtml+xml,text/vnd.wap.wml,*/*",
+			"X-Wap-Profile": `"http://nds1.nds.nokia.com/uaprof/N6300r100.xml"`,
+		},
+		DeviceType: mobiledetect.DeviceTypeFeaturePhone,
+	}
+	Googlebot = Profile{
+		Name:       "Googlebot",
+		UserAgent:  `Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`,
+		Headers:    map[string]string{"From": "googlebot(at)googlebot.com"},
+		DeviceType: mobiledetect.DeviceTypeDesktop,
+		Bot:        true,
+	}
+	SmartTV = Profile{
+		Name:       "Smart TV",
+		UserAgent:  `Mozilla/5.0 (SMART-TV; Linux; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36`,
+		DeviceType: mobiledetect.DeviceTypeDesktop,
+	}
+	DesktopChrome = Profile{
+		Name:      "Desktop Chrome",
+		UserAgent: `Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`,
+		Headers: map[string]string{
+			"Sec-CH-UA":          `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`,
+			"Sec-CH-UA-Mobile":   "?0",
+			"Sec-CH-UA-Platform": `"Windows"`,
+		},
+		DeviceType: mobiledetect.DeviceTypeDesktop,
+	}
+	DesktopSafari = Profile{
+		Name:       "Desktop Safari",
+		UserAgent:  `Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4_1) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Safari/605.1.15`,
+		DeviceType: mobiledetect.DeviceTypeDesktop,
+	}
+	DesktopFirefox = Profile{
+		Name:       "Desktop Firefox",
+		UserAgent:  `Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0`,
+		DeviceType: mobiledetect.DeviceTypeDesktop,
+	}
+)
+
+// Profiles lists every profile of the package.
+var Profiles = []Profile{
+	IPhone,
+	IPadDesktopMode,
+	Pixel,
+	GalaxyTab,
+	KindleFire,
+	KindlePaperwhite,
+	FeaturePhone,
+	Googlebot,
+	SmartTV,
+	DesktopChrome,
+	DesktopSafari,
+	DesktopFirefox,
+}
+
+// Apply sets the User-Agent and headers of the profile on r.
+func (p Profile) Apply(r *http.Request) {
+	r.Header.Set("User-Agent", p.UserAgent)
+	for name, value := range p.Headers {
+		r.Header.Set(name, value)
+	}
+}
+
+// NewRequest returns an incoming server request sent by the profile, see httptest.NewRequest.
+func NewRequest(p Profile, method, target string) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	p.Apply(r)
+	return r
+}
+
+// Transport is an http.RoundTripper sending requests as the profile, for end-to-end tests:
+//
+//	client := &http.Client{Transport: &mobiledetecttest.Transport{Profile: mobiledetecttest.IPhone}}
+type Transport struct {
+	Profile Profile
+	// Base sends the requests, http.DefaultTransport when nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip sends a copy of r carrying the headers of the profile.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	out := r.Clone(r.Context())
+	t.Profile.Apply(out)
+	base := t.Base
+	if nil == base {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(out)
+}
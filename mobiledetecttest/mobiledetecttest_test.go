@@ -0,0 +1,45 @@
+package mobiledetecttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/houseme/mobiledetect"
+)
+
+func TestProfiles(t *testing.T) {
+	for _, p := range Profiles {
+		md := mobiledetect.New(NewRequest(p, "GET", "/"), nil)
+		if deviceType := md.DeviceType(); p.DeviceType != deviceType {
+			t.Errorf("%s: expected %s got %s", p.Name, p.DeviceType, deviceType)
+		}
+		if bot := md.IsBot(); p.Bot != bot {
+			t.Errorf("%s: expected bot %t got %t", p.Name, p.Bot, bot)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var userAgent, mobile string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		mobile = r.Header.Get("Sec-CH-UA-Mobile")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{Profile: Pixel}}
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if nil != err {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if Pixel.UserAgent != userAgent || "?1" != mobile {
+		t.Errorf("Profile was not applied: %q %q", userAgent, mobile)
+	}
+	if "" != req.Header.Get("User-Agent") {
+		t.Error("The original request was modified")
+	}
+}
@@ -0,0 +1,149 @@
+package mobiledetect
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Override forces the device type of a request, e.g. for a "view desktop site" link
+// or for QA. The choice is read from a query parameter and persisted in an HMAC signed
+// cookie, both taking precedence over detection. DeviceTypeBot can't be forced, lest visitors
+// get the pages served to crawlers.
+type Override struct {
+	// QueryParam is the query parameter forcing a device type, as in ?device=desktop.
+	QueryParam string
+	// ClearValue is the QueryParam value which removes the override, as in ?device=auto.
+	ClearValue string
+	// CookieName is the name of the cookie persisting the choice.
+	CookieName string
+	// CookiePath and CookieDomain scope the cookie.
+	CookiePath   string
+	CookieDomain string
+	// MaxAge is how long the cookie keeps the choice.
+	MaxAge time.Duration
+	// Secret signs the cookie. Without it the choice is not persisted and cookies are ignored.
+	Secret []byte
+
+	now func() time.Time
+}
+
+// NewOverride creates an Override reading ?device= and persisting the choice for 30 days.
+func NewOverride(secret []byte) *Override {
+	return &Override{
+		QueryParam: "device",
+		ClearValue: "auto",
+		CookieName: "mobiledetect_device",
+		CookiePath: "/",
+		MaxAge:     30 * 24 * time.Hour,
+		Secret:     secret,
+	}
+}
+
+// Apply sets on md the device type forced by the request, if any. A device type given in
+// the query parameter is persisted in the cookie, the clear value deletes the cookie.
+// w may be nil, in which case the cookie is left untouched. Apply on a nil Override does nothing.
+func (o *Override) Apply(w http.ResponseWriter, r *http.Request, md *MobileDetect) {
+	if nil == o {
+		return
+	}
+
+	if "" != o.QueryParam {
+		if value := r.URL.Query().Get(o.QueryParam); "" != value {
+			if "" != o.ClearValue && strings.EqualFold(value, o.ClearValue) {
+				o.Clear(w)
+				md.SetOverride("")
+				return
+			}
+			if deviceType, ok := ParseDeviceType(value); ok && DeviceTypeBot != deviceType {
+				md.SetOverride(deviceType)
+				o.persist(w, deviceType)
+				return
+			}
+		}
+	}
+
+	if len(o.Secret) == 0 || "" == o.CookieName {
+		return
+	}
+	if cookie, err := r.Cookie(o.CookieName); nil == err {
+		if deviceType, ok := o.verify(cookie.Value); ok && DeviceTypeBot != deviceType {
+			md.SetOverride(deviceType)
+		}
+	}
+}
+
+// Clear deletes the override cookie.
+func (o *Override) Clear(w http.ResponseWriter) {
+	if nil == w || "" == o.CookieName {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     o.CookieName,
+		Path:     o.CookiePath,
+		Domain:   o.CookieDomain,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+func (o *Override) persist(w http.ResponseWriter, deviceType DeviceType) {
+	if nil == w || len(o.Secret) == 0 || "" == o.CookieName {
+		return
+	}
+	expires := o.clock().Add(o.MaxAge)
+	http.SetCookie(w, &http.Cookie{
+		Name:     o.CookieName,
+		Value:    o.sign(deviceType, expires),
+		Path:     o.CookiePath,
+		Domain:   o.CookieDomain,
+		Expires:  expires,
+		MaxAge:   int(o.MaxAge.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// sign returns the cookie value: the device type, its expiry and their signature, dot separated.
+func (o *Override) sign(deviceType DeviceType, expires time.Time) string {
+	payload := string(deviceType) + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + o.mac(payload)
+}
+
+func (o *Override) verify(value string) (DeviceType, bool) {
+	i := strings.LastIndex(value, ".")
+	if -1 == i {
+		return "", false
+	}
+	payload, signature := value[:i], value[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(o.mac(payload))) {
+		return "", false
+	}
+
+	parts := strings.SplitN(payload, ".", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if nil != err || o.clock().Unix() >= expires {
+		return "", false
+	}
+	return ParseDeviceType(parts[0])
+}
+
+func (o *Override) mac(payload string) string {
+	m := hmac.New(sha256.New, o.Secret)
+	m.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+func (o *Override) clock() time.Time {
+	if nil != o.now {
+		return o.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,109 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOverrideQueryAndCookie(t *testing.T) {
+	o := NewOverride([]byte("secret"))
+	now := time.Unix(1600000000, 0)
+	o.now = func() time.Time { return now }
+
+	r := newTestRequest(iPhoneUserAgent, nil)
+	r.URL.RawQuery = "device=desktop"
+	rec := httptest.NewRecorder()
+	detect := New(r, nil)
+	o.Apply(rec, r, detect)
+	if DeviceTypeDesktop != detect.DeviceType() || !detect.Overridden() {
+		t.Fatalf("Query parameter did not override the device type: %s", detect.DeviceType())
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || o.CookieName != cookies[0].Name {
+		t.Fatalf("Override was not persisted: %v", cookies)
+	}
+
+	r = newTestRequest(iPhoneUserAgent, nil)
+	r.AddCookie(cookies[0])
+	detect = New(r, nil)
+	o.Apply(nil, r, detect)
+	if DeviceTypeDesktop != detect.DeviceType() {
+		t.Errorf("Cookie did not override the device type: %s", detect.DeviceType())
+	}
+
+	now = now.Add(o.MaxAge)
+	detect = New(r, nil)
+	o.Apply(nil, r, detect)
+	if detect.Overridden() {
+		t.Error("Expired cookie should be ignored")
+	}
+}
+
+func TestOverrideRejectsTamperedCookie(t *testing.T) {
+	o := NewOverride([]byte("secret"))
+	forged := NewOverride([]byte("other")).sign(DeviceTypeTablet, time.Now().Add(time.Hour))
+	for _, value := range []string{forged, "Tablet", "Tablet.99999999999", ""} {
+		r := newTestRequest(iPhoneUserAgent, nil)
+		r.AddCookie(&http.Cookie{Name: o.CookieName, Value: value})
+		detect := New(r, nil)
+		o.Apply(nil, r, detect)
+		if detect.Overridden() {
+			t.Errorf("Cookie %q should be rejected", value)
+		}
+	}
+}
+
+func TestOverrideRejectsBot(t *testing.T) {
+	o := NewOverride([]byte("secret"))
+	r := newTestRequest(iPhoneUserAgent, nil)
+	r.URL.RawQuery = "device=bot"
+	r.AddCookie(&http.Cookie{Name: o.CookieName, Value: o.sign(DeviceTypeBot, time.Now().Add(time.Hour))})
+	md := New(r, nil)
+	o.Apply(nil, r, md)
+	if md.Overridden() {
+		t.Errorf("Expected the bot device type to be refused, got %s", md.DeviceType())
+	}
+}
+
+func TestOverrideClear(t *testing.T) {
+	o := NewOverride([]byte("secret"))
+	r := newTestRequest(iPhoneUserAgent, nil)
+	r.AddCookie(&http.Cookie{Name: o.CookieName, Value: o.sign(DeviceTypeDesktop, time.Now().Add(time.Hour))})
+	r.URL.RawQuery = "device=auto"
+	rec := httptest.NewRecorder()
+	detect := New(r, nil)
+	o.Apply(rec, r, detect)
+	if detect.Overridden() || DeviceTypeMobile != detect.DeviceType() {
+		t.Errorf("Override was not cleared: %s", detect.DeviceType())
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Errorf("Cookie was not deleted: %v", cookies)
+	}
+}
+
+func TestOverrideHandlers(t *testing.T) {
+	o := NewOverride(nil)
+	deviceHandler := &basicMethodsStruct{}
+	r := newTestRequest(iPhoneUserAgent, nil)
+	r.URL.RawQuery = "device=tablet"
+	OverrideHandler(deviceHandler, nil, o).ServeHTTP(httptest.NewRecorder(), r)
+	if "tablet" != deviceHandler.handlerCalled {
+		t.Errorf("actual: %s instead: tablet", deviceHandler.handlerCalled)
+	}
+
+	var overridden bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		deviceHandler.ServeHTTP(w, r)
+		if md, ok := FromContext(r.Context()); ok {
+			overridden = md.Overridden()
+		}
+	})
+	OverrideHandlerMux(mux, nil, o).ServeHTTP(httptest.NewRecorder(), r)
+	if "Tablet" != deviceHandler.handlerCalled || !overridden {
+		t.Errorf("actual: %s (overridden %t) instead: Tablet", deviceHandler.handlerCalled, overridden)
+	}
+}
@@ -0,0 +1,84 @@
+package mobiledetect
+
+import (
+	"strings"
+)
+
+// Values of the user preference media hints.
+const (
+	ColorSchemeLight = "light"
+	ColorSchemeDark  = "dark"
+
+	ContrastNoPreference = "no-preference"
+	ContrastMore         = "more"
+	ContrastLess         = "less"
+	ContrastCustom       = "custom"
+)
+
+// Preferences holds the user preference media features sent through the
+// Sec-CH-Prefers-* client hints. Empty values mean the hint was not sent,
+// except on e-readers, which default to reduced motion and more contrast.
+type Preferences struct {
+	// ColorScheme is ColorSchemeLight or ColorSchemeDark.
+	ColorScheme string
+	// ReducedMotion is set when the user asked to minimize non-essential motion.
+	ReducedMotion bool
+	// ReducedTransparency is set when the user asked to reduce transparent or translucent layers.
+	ReducedTransparency bool
+	// Contrast is one of the Contrast constants.
+	Contrast string
+}
+
+// Preferences returns the user preference media hints of the request.
+func (md *MobileDetect) Preferences() Preferences {
+	p := Preferences{
+		ColorScheme:         hintToken(md.header("Sec-CH-Prefers-Color-Scheme")),
+		ReducedMotion:       "reduce" == hintToken(md.header("Sec-CH-Prefers-Reduced-Motion")),
+		ReducedTransparency: "reduce" == hintToken(md.header("Sec-CH-Prefers-Reduced-Transparency")),
+		Contrast:            hintToken(md.header("Sec-CH-Prefers-Contrast")),
+	}
+	if DeviceTypeEReader == md.DeviceType() {
+		if "" == md.header("Sec-CH-Prefers-Reduced-Motion") {
+			p.ReducedMotion = true
+		}
+		if "" == p.Contrast {
+			p.Contrast = ContrastMore
+		}
+	}
+	return p
+}
+
+// PrefersDark reports whether the user prefers a dark color scheme.
+func (p Preferences) PrefersDark() bool {
+	return ColorSchemeDark == p.ColorScheme
+}
+
+// PrefersLight reports whether the user prefers a light color scheme.
+func (p Preferences) PrefersLight() bool {
+	return ColorSchemeLight == p.ColorScheme
+}
+
+// PrefersReducedMotion reports whether the user prefers reduced motion.
+func (p Preferences) PrefersReducedMotion() bool {
+	return p.ReducedMotion
+}
+
+// PrefersReducedTransparency reports whether the user prefers reduced transparency.
+func (p Preferences) PrefersReducedTransparency() bool {
+	return p.ReducedTransparency
+}
+
+// PrefersMoreContrast reports whether the user prefers a higher contrast.
+func (p Preferences) PrefersMoreContrast() bool {
+	return ContrastMore == p.Contrast
+}
+
+// PrefersLessContrast reports whether the user prefers a lower contrast.
+func (p Preferences) PrefersLessContrast() bool {
+	return ContrastLess == p.Contrast
+}
+
+// hintToken normalizes a structured header token, which some clients send quoted.
+func hintToken(value string) string {
+	return strings.ToLower(strings.Trim(value, `" `))
+}
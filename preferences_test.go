@@ -0,0 +1,33 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestPreferences(t *testing.T) {
+	tests := []struct {
+		headers  map[string]string
+		expected Preferences
+	}{
+		{nil, Preferences{}},
+		{map[string]string{"Sec-CH-Prefers-Color-Scheme": "dark"}, Preferences{ColorScheme: ColorSchemeDark}},
+		{map[string]string{"Sec-CH-Prefers-Color-Scheme": `"light"`}, Preferences{ColorScheme: ColorSchemeLight}},
+		{map[string]string{"Sec-CH-Prefers-Reduced-Motion": "reduce", "Sec-CH-Prefers-Reduced-Transparency": "no-preference"},
+			Preferences{ReducedMotion: true}},
+		{map[string]string{"Sec-CH-Prefers-Reduced-Transparency": "reduce", "Sec-CH-Prefers-Contrast": "more"},
+			Preferences{ReducedTransparency: true, Contrast: ContrastMore}},
+	}
+
+	for _, test := range tests {
+		got := New(newTestRequest(iPhoneUserAgent, test.headers), nil).Preferences()
+		if test.expected != got {
+			t.Errorf("For headers %v, expected %+v got %+v", test.headers, test.expected, got)
+		}
+	}
+
+	p := Preferences{ColorScheme: ColorSchemeDark, ReducedMotion: true, Contrast: ContrastLess}
+	if !p.PrefersDark() || p.PrefersLight() || !p.PrefersReducedMotion() || p.PrefersReducedTransparency() ||
+		p.PrefersMoreContrast() || !p.PrefersLessContrast() {
+		t.Errorf("Accessors do not match %+v", p)
+	}
+}
@@ -49,6 +49,11 @@ const (
 	PropWindowsNt
 	PropSymbian
 	PropWebos
+	PropMacOS
+	PropChromeOS
+	PropEdge
+	PropVivaldi
+	PropYaBrowser
 )
 
 var (
@@ -95,6 +100,26 @@ var (
 		"windows nt":       PropWindowsNt,
 		"symbian":          PropSymbian,
 		"webos":            PropWebos,
+		"mac os x":         PropMacOS,
+		"cros":             PropChromeOS,
+		"edge":             PropEdge,
+		"vivaldi":          PropVivaldi,
+		"yabrowser":        PropYaBrowser,
+	}
+
+	// propertyAliases lets Version take the desktop names accepted by Is.
+	propertyAliases = map[string]int{
+		"windows":        PropWindowsNt,
+		"macos":          PropMacOS,
+		"chromeos":       PropChromeOS,
+		"desktopedge":    PropEdge,
+		"desktopopera":   PropOpera,
+		"desktopvivaldi": PropVivaldi,
+		"desktopyandex":  PropYaBrowser,
+		"yandex":         PropYaBrowser,
+		"desktopchrome":  PropChrome,
+		"desktopfirefox": PropFirefox,
+		"desktopsafari":  PropSafari,
 	}
 
 	// Properties helps parsing User Agent string, extracting useful segments of text.
@@ -198,6 +223,18 @@ var (
 		[]string{`SymbianOS/[VER]`, `Symbian/[VER]`},
 		// PROP_WEBOS:
 		[]string{`webOS/[VER]`, `hpwOS/[VER];`},
+		// Desktop
+		// PROP_MAC_OS:
+		[]string{`Mac OS X [VER]`},
+		// The platform version, after the architecture.
+		// PROP_CHROME_OS:
+		[]string{`CrOS \w+ [VER]`},
+		// PROP_EDGE:
+		[]string{`Edg/[VER]`, `Edge/[VER]`, `EdgA/[VER]`, `EdgiOS/[VER]`},
+		// PROP_VIVALDI:
+		[]string{`Vivaldi/[VER]`},
+		// PROP_YABROWSER:
+		[]string{`YaBrowser/[VER]`},
 	}
 )
 
@@ -250,11 +287,13 @@ func (p *properties) version(propertyVal int, userAgent string) string {
 
 func (p *properties) nameToKey(propertyName string) int {
 	propertyName = strings.ToLower(propertyName)
-	propertyVal, ok := propertiesNameToVal[propertyName]
-	if !ok {
-		return -1
+	if propertyVal, ok := propertiesNameToVal[propertyName]; ok {
+		return propertyVal
+	}
+	if propertyVal, ok := propertyAliases[propertyName]; ok {
+		return propertyVal
 	}
-	return propertyVal
+	return -1
 }
 
 func (p *properties) versionByName(propertyName, userAgent string) string {
@@ -0,0 +1,145 @@
+package mobiledetect
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/houseme/mobiledetect/ua"
+)
+
+// Headers set by Enricher on proxied requests.
+const (
+	HeaderDeviceType      = "X-Device-Type"
+	HeaderDeviceVendor    = "X-Device-Vendor"
+	HeaderDeviceOS        = "X-Device-OS"
+	HeaderDeviceOSVersion = "X-Device-OS-Version"
+	HeaderDeviceGrade     = "X-Device-Grade"
+	HeaderIsBot           = "X-Is-Bot"
+	// HeaderDeviceSignature holds the HMAC-SHA256 of the other headers when Enricher has a secret.
+	HeaderDeviceSignature = "X-Device-Signature"
+)
+
+// DeviceHeaders lists the headers set by Enricher, in signing order.
+var DeviceHeaders = []string{
+	HeaderDeviceType,
+	HeaderDeviceVendor,
+	HeaderDeviceOS,
+	HeaderDeviceOSVersion,
+	HeaderDeviceGrade,
+	HeaderIsBot,
+}
+
+// Enricher forwards the classification of proxied requests to upstream services as headers,
+// for backends which can't run the detection themselves. It is meant for the Rewrite hook
+// of an httputil.ReverseProxy, which runs once the hop-by-hop headers are removed:
+//
+//	enricher := mobiledetect.NewEnricher(nil, secret)
+//	proxy := &httputil.ReverseProxy{Rewrite: func(pr *httputil.ProxyRequest) {
+//		pr.SetURL(target)
+//		enricher.Rewrite(pr)
+//	}}
+type Enricher struct {
+	// Secret, when set, signs the headers in HeaderDeviceSignature, see VerifyDeviceHeaders.
+	Secret []byte
+	// Override, when set, lets users force the device type.
+	Override *Override
+
+	rules *rules
+}
+
+// NewEnricher creates an Enricher using the given rules, or the default ones when nil.
+func NewEnricher(rules *rules, secret []byte) *Enricher {
+	return &Enricher{Secret: secret, rules: rules}
+}
+
+// Director returns a director calling director, which may be nil, then Enrich.
+// It is unsafe: the proxy removes the headers listed in Connection after the director runs,
+// so a client sending "Connection: X-Device-Type" would get the device headers dropped.
+// Enrich removes the device headers from Connection, but use Rewrite where available.
+func (e *Enricher) Director(director func(*http.Request)) func(*http.Request) {
+	return func(out *http.Request) {
+		if nil != director {
+			director(out)
+		}
+		e.Enrich(out)
+	}
+}
+
+// Enrich replaces the device headers of the outgoing request, dropping any copy sent by the client.
+// The MobileDetect stored in the request context is reused when present, see FromContext.
+func (e *Enricher) Enrich(out *http.Request) {
+	for _, name := range DeviceHeaders {
+		out.Header.Del(name)
+	}
+	out.Header.Del(HeaderDeviceSignature)
+	dropConnectionTokens(out.Header, append(append([]string{}, DeviceHeaders...), HeaderDeviceSignature))
+
+	md := detect(out, e.rules)
+	e.Override.Apply(nil, out, md)
+	agent := ua.New(md.userAgent)
+
+	out.Header.Set(HeaderDeviceType, string(md.DeviceType()))
+	setNonEmpty(out.Header, HeaderDeviceVendor, md.Vendor())
+	setNonEmpty(out.Header, HeaderDeviceOS, agent.ShortOS())
+	setNonEmpty(out.Header, HeaderDeviceOSVersion, agent.OSVersion())
+	out.Header.Set(HeaderDeviceGrade, md.MobileGrade())
+	out.Header.Set(HeaderIsBot, strconv.FormatBool(md.IsBot()))
+
+	if len(e.Secret) > 0 {
+		out.Header.Set(HeaderDeviceSignature, signDeviceHeaders(out.Header, e.Secret))
+	}
+}
+
+// VerifyDeviceHeaders reports whether the device headers received by an upstream service
+// were signed by an Enricher using secret.
+func VerifyDeviceHeaders(h http.Header, secret []byte) bool {
+	signature := h.Get(HeaderDeviceSignature)
+	if "" == signature || len(secret) == 0 {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(signDeviceHeaders(h, secret)))
+}
+
+// signDeviceHeaders signs the name: value lines of the device headers.
+func signDeviceHeaders(h http.Header, secret []byte) string {
+	m := hmac.New(sha256.New, secret)
+	for _, name := range DeviceHeaders {
+		m.Write([]byte(name + ": " + h.Get(name) + "\n"))
+	}
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// dropConnectionTokens removes the given header names from the Connection header.
+func dropConnectionTokens(h http.Header, names []string) {
+	values := h.Values("Connection")
+	if len(values) == 0 {
+		return
+	}
+	var kept []string
+	for _, value := range values {
+		for _, token := range strings.Split(value, ",") {
+			token = strings.TrimSpace(token)
+			drop := "" == token
+			for _, name := range names {
+				drop = drop || strings.EqualFold(token, name)
+			}
+			if !drop {
+				kept = append(kept, token)
+			}
+		}
+	}
+	h.Del("Connection")
+	if len(kept) > 0 {
+		h.Set("Connection", strings.Join(kept, ", "))
+	}
+}
+
+func setNonEmpty(h http.Header, name, value string) {
+	if "" != value {
+		h.Set(name, value)
+	}
+}
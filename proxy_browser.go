@@ -0,0 +1,70 @@
+package mobiledetect
+
+import (
+	"strings"
+)
+
+// Proxy browser vendors.
+const (
+	ProxyOperaMini = "Opera Mini"
+	ProxyUCBrowser = "UC Browser"
+	ProxyPuffin    = "Puffin"
+)
+
+// ProxyBrowser describes a browser rendering pages on its vendor's servers, and sending the
+// device a compressed or pre-rendered version of them.
+type ProxyBrowser struct {
+	// Vendor is one of the Proxy* constants.
+	Vendor string `json:"vendor"`
+	// DataSaving reports whether the extreme or data saving mode is likely on, the page being
+	// rendered by the proxy instead of the device.
+	DataSaving bool `json:"dataSaving"`
+	// LimitedJS reports whether scripts only run for a short time, on the proxy, or not at all.
+	LimitedJS bool `json:"limitedJS"`
+	// Features are the X-OperaMini-Features values, e.g. "advanced", "touch" or "viewport".
+	Features []string `json:"features,omitempty"`
+	// DeviceUserAgent is the User-Agent of the device's own browser as forwarded by the proxy, if any.
+	DeviceUserAgent string `json:"deviceUserAgent,omitempty"`
+}
+
+// ProxyBrowser returns the proxy browser of the request, and false when it isn't one.
+// Opera Mini is recognized by its token, the X-OperaMini-* headers or the OBML Accept type;
+// the Opera Mobi token is Opera Mobile, which renders pages itself. UC Browser is only reported
+// in speed mode: on J2ME, with the UCWEB token or through its proxy, which adds X-UCBrowser-Device-UA.
+func (md *MobileDetect) ProxyBrowser() (ProxyBrowser, bool) {
+	features := md.header("X-OperaMini-Features")
+	if md.match(`Opera Mini`) || "" != features || "" != md.header("X-OperaMini-Phone-UA") ||
+		strings.Contains(md.header("Accept"), "application/x-obml2d") {
+		p := ProxyBrowser{
+			Vendor:          ProxyOperaMini,
+			DataSaving:      true,
+			LimitedJS:       true,
+			DeviceUserAgent: md.header("X-OperaMini-Phone-UA"),
+		}
+		for _, feature := range strings.Split(features, ",") {
+			if feature = strings.TrimSpace(feature); "" != feature {
+				p.Features = append(p.Features, feature)
+			}
+		}
+		return p, true
+	}
+
+	if md.IsKey(PUFFIN) {
+		return ProxyBrowser{Vendor: ProxyPuffin, DataSaving: true, LimitedJS: true}, true
+	}
+
+	deviceUserAgent := md.header("X-UCBrowser-Device-UA")
+	if md.IsKey(UCBROWSER) || "" != deviceUserAgent {
+		p := ProxyBrowser{
+			Vendor:          ProxyUCBrowser,
+			LimitedJS:       md.IsKey(JAVAOS) || md.match(`UCWEB`),
+			DeviceUserAgent: deviceUserAgent,
+		}
+		p.DataSaving = p.LimitedJS || "" != deviceUserAgent
+		if !p.DataSaving {
+			return ProxyBrowser{}, false
+		}
+		return p, true
+	}
+	return ProxyBrowser{}, false
+}
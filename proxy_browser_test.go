@@ -0,0 +1,81 @@
+package mobiledetect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProxyBrowser(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		headers   map[string]string
+		expected  ProxyBrowser
+		ok        bool
+	}{
+		{
+			`Opera/9.80 (Android; Opera Mini/36.2.2254/119.132; U; id) Presto/2.12.423 Version/12.16`,
+			map[string]string{
+				"X-OperaMini-Features": "advanced, camera, download, file_system, folding, httpping, pingback, routing, touch, viewport",
+				"X-OperaMini-Phone-UA": "Mozilla/5.0 (Linux; Android 9; SM-J260G) AppleWebKit/537.36",
+			},
+			ProxyBrowser{
+				Vendor:          ProxyOperaMini,
+				DataSaving:      true,
+				LimitedJS:       true,
+				Features:        []string{"advanced", "camera", "download", "file_system", "folding", "httpping", "pingback", "routing", "touch", "viewport"},
+				DeviceUserAgent: "Mozilla/5.0 (Linux; Android 9; SM-J260G) AppleWebKit/537.36",
+			},
+			true,
+		},
+		{
+			`Opera/9.80 (J2ME/MIDP; Opera Mini/4.2.14912/870; U; id) Presto/2.4.15`,
+			nil,
+			ProxyBrowser{Vendor: ProxyOperaMini, DataSaving: true, LimitedJS: true},
+			true,
+		},
+		{
+			`Nokia6300/2.0`,
+			map[string]string{"Accept": "application/x-obml2d, */*"},
+			ProxyBrowser{Vendor: ProxyOperaMini, DataSaving: true, LimitedJS: true},
+			true,
+		},
+		{
+			`Opera/9.80 (Android 2.3.3; Linux; Opera Mobi/ADR-1111101157; U; es-ES) Presto/2.9.201 Version/11.50`,
+			nil,
+			ProxyBrowser{},
+			false,
+		},
+		{
+			`Mozilla/5.0 (Linux; Android 9; SM-G960F Build/PPR1.180610.011; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/74.0.3729.136 Mobile Safari/537.36 Puffin/8.3.0.41624AP`,
+			nil,
+			ProxyBrowser{Vendor: ProxyPuffin, DataSaving: true, LimitedJS: true},
+			true,
+		},
+		{
+			`Nokia5130c-2/2.0 (07.97) Profile/MIDP-2.1 Configuration/CLDC-1.1 nokia5130c-2/UC Browser7.5.1.77/69/351 UNTRUSTED/1.0`,
+			nil,
+			ProxyBrowser{Vendor: ProxyUCBrowser, DataSaving: true, LimitedJS: true},
+			true,
+		},
+		{
+			`Mozilla/5.0 (Linux; U; Android 10; en-US; RMX1911 Build/QKQ1.200209.002) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/78.0.3904.108 UCBrowser/13.4.0.1306 Mobile Safari/537.36`,
+			map[string]string{"X-UCBrowser-Device-UA": "Mozilla/5.0 (Linux; Android 10; RMX1911)"},
+			ProxyBrowser{Vendor: ProxyUCBrowser, DataSaving: true, DeviceUserAgent: "Mozilla/5.0 (Linux; Android 10; RMX1911)"},
+			true,
+		},
+		{
+			`Mozilla/5.0 (Linux; U; Android 10; en-US; RMX1911 Build/QKQ1.200209.002) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/78.0.3904.108 UCBrowser/13.4.0.1306 Mobile Safari/537.36`,
+			nil,
+			ProxyBrowser{},
+			false,
+		},
+		{iPhoneUserAgent, nil, ProxyBrowser{}, false},
+		{desktopUserAgent, nil, ProxyBrowser{}, false},
+	}
+	for _, test := range tests {
+		p, ok := New(newTestRequest(test.userAgent, test.headers), nil).ProxyBrowser()
+		if test.ok != ok || !reflect.DeepEqual(test.expected, p) {
+			t.Errorf("For userAgent %s, expected %+v %v got %+v %v", test.userAgent, test.expected, test.ok, p, ok)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build go1.20
+// +build go1.20
+
+package mobiledetect
+
+import (
+	"net/http/httputil"
+)
+
+// Rewrite enriches the outgoing request of an httputil.ReverseProxy Rewrite hook, see Enrich.
+// Unlike Director it runs after the hop-by-hop headers are removed, so the client can't have
+// the device headers dropped by listing them in Connection.
+func (e *Enricher) Rewrite(pr *httputil.ProxyRequest) {
+	e.Enrich(pr.Out)
+}
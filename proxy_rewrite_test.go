@@ -0,0 +1,38 @@
+//go:build go1.20
+// +build go1.20
+
+package mobiledetect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+)
+
+func TestEnricherRewrite(t *testing.T) {
+	secret := []byte("secret")
+	var received http.Header
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer backend.Close()
+
+	target, _ := url.Parse(backend.URL)
+	enricher := NewEnricher(nil, secret)
+	proxy := &httputil.ReverseProxy{Rewrite: func(pr *httputil.ProxyRequest) {
+		pr.SetURL(target)
+		enricher.Rewrite(pr)
+	}}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("User-Agent", iPadUserAgent)
+	r.Header.Set(HeaderDeviceType, "Desktop")
+	r.Header.Set("Connection", "X-Device-Type, X-Device-Signature, X-Device-Grade")
+	proxy.ServeHTTP(httptest.NewRecorder(), r)
+
+	if "Tablet" != received.Get(HeaderDeviceType) || !VerifyDeviceHeaders(received, secret) {
+		t.Errorf("Backend received %v", received)
+	}
+}
@@ -0,0 +1,94 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+)
+
+func TestEnrich(t *testing.T) {
+	e := NewEnricher(nil, nil)
+	tests := []struct {
+		userAgent string
+		expected  map[string]string
+	}{
+		{iPhoneUserAgent, map[string]string{
+			HeaderDeviceType:      "Mobile",
+			HeaderDeviceVendor:    "Apple",
+			HeaderDeviceOS:        "iOS",
+			HeaderDeviceOSVersion: "13.2.3",
+			HeaderDeviceGrade:     "A",
+			HeaderIsBot:           "false",
+		}},
+		{desktopUserAgent, map[string]string{
+			HeaderDeviceType:      "Desktop",
+			HeaderDeviceVendor:    "",
+			HeaderDeviceOS:        "Windows",
+			HeaderDeviceOSVersion: "10.0",
+			HeaderIsBot:           "false",
+		}},
+		{`Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`, map[string]string{
+			HeaderDeviceType: "Desktop",
+			HeaderIsBot:      "true",
+		}},
+	}
+
+	for _, test := range tests {
+		r := newTestRequest(test.userAgent, map[string]string{
+			HeaderDeviceType:      "Tablet",
+			HeaderDeviceVendor:    "Spoofed",
+			HeaderIsBot:           "false",
+			HeaderDeviceSignature: "forged",
+		})
+		e.Enrich(r)
+		for name, expected := range test.expected {
+			if actual := r.Header.Get(name); expected != actual {
+				t.Errorf("For %s, expected %s: %q got %q", test.userAgent, name, expected, actual)
+			}
+		}
+		if "" != r.Header.Get(HeaderDeviceSignature) {
+			t.Errorf("Client supplied signature was kept")
+		}
+	}
+}
+
+func TestEnricherSignature(t *testing.T) {
+	secret := []byte("secret")
+	r := newTestRequest(iPhoneUserAgent, nil)
+	NewEnricher(nil, secret).Enrich(r)
+	if !VerifyDeviceHeaders(r.Header, secret) {
+		t.Fatal("Signature should verify")
+	}
+	if VerifyDeviceHeaders(r.Header, []byte("other")) {
+		t.Error("Signature should not verify with another secret")
+	}
+	r.Header.Set(HeaderDeviceType, "Desktop")
+	if VerifyDeviceHeaders(r.Header, secret) {
+		t.Error("Signature should not verify tampered headers")
+	}
+}
+
+func TestEnricherDirector(t *testing.T) {
+	secret := []byte("secret")
+	var received http.Header
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer backend.Close()
+
+	target, _ := url.Parse(backend.URL)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Director = NewEnricher(nil, secret).Director(proxy.Director)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("User-Agent", iPadUserAgent)
+	r.Header.Set(HeaderDeviceType, "Desktop")
+	r.Header.Set("Connection", "X-Device-Type, X-Device-Signature")
+	proxy.ServeHTTP(httptest.NewRecorder(), r)
+
+	if "Tablet" != received.Get(HeaderDeviceType) || !VerifyDeviceHeaders(received, secret) {
+		t.Errorf("Backend received %v", received)
+	}
+}
@@ -0,0 +1,213 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// TabletPolicy tells a Redirector which site tablets belong to.
+type TabletPolicy int
+
+const (
+	// TabletsToDesktop sends tablets to the desktop site.
+	TabletsToDesktop TabletPolicy = iota
+	// TabletsToMobile sends tablets to the mobile site.
+	TabletsToMobile
+	// TabletsStay never redirects tablets.
+	TabletsStay
+)
+
+// Redirector sends phones to the mobile site and desktop users back to the desktop site.
+// The mobile site is either a separate host (m.example.com) or a path prefix (/m).
+// Crawlers are never redirected, even when following a link forcing the device type, nor are
+// the requests other than GET and HEAD, whose body a redirect would drop. A device type forced
+// by the user is honored.
+type Redirector struct {
+	// MobileHost and DesktopHost are the hosts of both sites, e.g. m.example.com and www.example.com.
+	MobileHost  string
+	DesktopHost string
+	// MobilePrefix is the path prefix of the mobile site, used when MobileHost is empty.
+	MobilePrefix string
+	// Tablets tells which site tablets belong to.
+	Tablets TabletPolicy
+	// Exclude lists the paths never redirected, such as APIs and assets.
+	Exclude []*regexp.Regexp
+	// Override, when set, lets users force the device type.
+	Override *Override
+	// Code is the redirect status code, http.StatusFound by default.
+	Code int
+	// AlternateMedia is the media query announced with the mobile alternate link.
+	AlternateMedia string
+
+	rules *rules
+}
+
+// NewRedirector creates a Redirector using the given rules, or the default ones when nil.
+func NewRedirector(rules *rules, mobileHost, desktopHost string) *Redirector {
+	return &Redirector{
+		MobileHost:     mobileHost,
+		DesktopHost:    desktopHost,
+		Code:           http.StatusFound,
+		AlternateMedia: "only screen and (max-width: 640px)",
+		rules:          rules,
+	}
+}
+
+// Handler redirects the requests made to the wrong site and serves the others with next,
+// announcing the other site through Link rel=alternate or rel=canonical headers.
+func (rd *Redirector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, re := range rd.Exclude {
+			if re.MatchString(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		md := detect(r, rd.rules)
+		if !md.IsBot() {
+			rd.Override.Apply(w, r, md)
+		}
+
+		w.Header().Add("Vary", "User-Agent")
+		if nil != rd.Override {
+			w.Header().Add("Vary", "Cookie")
+		}
+
+		onMobile := rd.onMobileSite(r)
+		if target := rd.target(r, md, onMobile); "" != target {
+			code := rd.Code
+			if 0 == code {
+				code = http.StatusFound
+			}
+			http.Redirect(w, r, target, code)
+			return
+		}
+
+		if onMobile {
+			w.Header().Add("Link", `<`+rd.desktopURL(r)+`>; rel="canonical"`)
+		} else if "" != rd.AlternateMedia {
+			w.Header().Add("Link", `<`+rd.mobileURL(r)+`>; rel="alternate"; media="`+rd.AlternateMedia+`"`)
+		} else {
+			w.Header().Add("Link", `<`+rd.mobileURL(r)+`>; rel="alternate"`)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// target returns the URL the request must be redirected to, or an empty string.
+func (rd *Redirector) target(r *http.Request, md *MobileDetect, onMobile bool) string {
+	if http.MethodGet != r.Method && http.MethodHead != r.Method {
+		return ""
+	}
+	if md.IsBot() {
+		return ""
+	}
+
+	var wantsMobile bool
+	switch md.DeviceType() {
+	case DeviceTypeMobile, DeviceTypeFeaturePhone:
+		wantsMobile = true
+	case DeviceTypeTablet, DeviceTypeEReader:
+		if TabletsStay == rd.Tablets {
+			return ""
+		}
+		wantsMobile = TabletsToMobile == rd.Tablets
+	}
+	if wantsMobile == onMobile {
+		return ""
+	}
+
+	target := rd.desktopURL(r)
+	if wantsMobile {
+		target = rd.mobileURL(r)
+	}
+	// Loop protection: never redirect to the current URL, nor back to the page the user comes from.
+	if target == rd.currentURL(r) || sameURL(target, r.Referer()) {
+		return ""
+	}
+	return target
+}
+
+func (rd *Redirector) onMobileSite(r *http.Request) bool {
+	if "" != rd.MobileHost {
+		return strings.EqualFold(stripPort(r.Host), stripPort(rd.MobileHost))
+	}
+	return "" != rd.MobilePrefix && hasPathPrefix(r.URL.Path, rd.MobilePrefix)
+}
+
+func (rd *Redirector) mobileURL(r *http.Request) string {
+	if "" != rd.MobileHost {
+		return scheme(r) + "://" + rd.MobileHost + r.URL.RequestURI()
+	}
+	path := r.URL.Path
+	if !hasPathPrefix(path, rd.MobilePrefix) {
+		path = strings.TrimSuffix(rd.MobilePrefix, "/") + path
+	}
+	return scheme(r) + "://" + r.Host + withQuery(path, r.URL.RawQuery)
+}
+
+func (rd *Redirector) desktopURL(r *http.Request) string {
+	if "" != rd.MobileHost {
+		host := rd.DesktopHost
+		if "" == host {
+			host = r.Host
+		}
+		return scheme(r) + "://" + host + r.URL.RequestURI()
+	}
+	path := r.URL.Path
+	if hasPathPrefix(path, rd.MobilePrefix) {
+		path = "/" + strings.TrimLeft(path[len(strings.TrimSuffix(rd.MobilePrefix, "/")):], "/")
+	}
+	return scheme(r) + "://" + r.Host + withQuery(path, r.URL.RawQuery)
+}
+
+func (rd *Redirector) currentURL(r *http.Request) string {
+	return scheme(r) + "://" + r.Host + r.URL.RequestURI()
+}
+
+// scheme returns the scheme of the request, as told by X-Forwarded-Proto when it is http or https.
+func scheme(r *http.Request) string {
+	switch proto := strings.ToLower(strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Proto"), ",")[0])); proto {
+	case "http", "https":
+		return proto
+	}
+	if nil != r.TLS {
+		return "https"
+	}
+	return "http"
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return "" != prefix && (path == prefix || strings.HasPrefix(path, prefix+"/"))
+}
+
+func withQuery(path, rawQuery string) string {
+	if "" == rawQuery {
+		return path
+	}
+	return path + "?" + rawQuery
+}
+
+func stripPort(host string) string {
+	if i := strings.LastIndex(host, ":"); -1 != i && !strings.Contains(host[i:], "]") {
+		return host[:i]
+	}
+	return host
+}
+
+// sameURL compares two URLs ignoring the query string.
+func sameURL(a, b string) bool {
+	ua, err := url.Parse(a)
+	if nil != err || "" == b {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if nil != err {
+		return false
+	}
+	return strings.EqualFold(ua.Host, ub.Host) && ua.Path == ub.Path
+}
@@ -0,0 +1,115 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedirectorHosts(t *testing.T) {
+	googlebot := `Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/41.0.2272.96 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)`
+	rd := NewRedirector(nil, "m.example.com", "www.example.com")
+	rd.Exclude = []*regexp.Regexp{regexp.MustCompile(`^/(api|static)/`)}
+	rd.Override = NewOverride(nil)
+
+	tests := []struct {
+		url       string
+		userAgent string
+		referer   string
+		location  string
+		link      string
+	}{
+		{"http://www.example.com/news?id=1", iPhoneUserAgent, "", "http://m.example.com/news?id=1", ""},
+		{"http://m.example.com/news?id=1", desktopUserAgent, "", "http://www.example.com/news?id=1", ""},
+		{"http://m.example.com/news", iPhoneUserAgent, "", "", `<http://www.example.com/news>; rel="canonical"`},
+		{"http://www.example.com/news", desktopUserAgent, "", "", `<http://m.example.com/news>; rel="alternate"; media="only screen and (max-width: 640px)"`},
+		{"http://www.example.com/news", iPadUserAgent, "", "", `<http://m.example.com/news>; rel="alternate"`},
+		{"http://www.example.com/news", googlebot, "", "", `<http://m.example.com/news>; rel="alternate"`},
+		{"http://www.example.com/news?device=mobile", googlebot, "", "", `<http://m.example.com/news?device=mobile>; rel="alternate"`},
+		{"http://www.example.com/api/items", iPhoneUserAgent, "", "", ""},
+		{"http://www.example.com/news", iPhoneUserAgent, "http://m.example.com/news", "", `<http://m.example.com/news>; rel="alternate"`},
+		{"http://m.example.com/news?device=desktop", iPhoneUserAgent, "", "http://www.example.com/news?device=desktop", ""},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", test.url, nil)
+		r.Header.Set("User-Agent", test.userAgent)
+		if "" != test.referer {
+			r.Header.Set("Referer", test.referer)
+		}
+		rec := httptest.NewRecorder()
+		rd.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rec, r)
+
+		if location := rec.Header().Get("Location"); test.location != location {
+			t.Errorf("For %s, expected redirect to %q got %q", test.url, test.location, location)
+		}
+		if link := rec.Header().Get("Link"); !strings.HasPrefix(link, test.link) {
+			t.Errorf("For %s, expected link %q got %q", test.url, test.link, link)
+		}
+		if test.userAgent == googlebot && "" != rec.Header().Get("Set-Cookie") {
+			t.Errorf("For %s, expected no override cookie for a crawler got %q", test.url, rec.Header().Get("Set-Cookie"))
+		}
+	}
+}
+
+func TestRedirectorPrefix(t *testing.T) {
+	rd := NewRedirector(nil, "", "")
+	rd.MobilePrefix = "/m"
+	rd.Tablets = TabletsToMobile
+
+	tests := []struct {
+		url       string
+		userAgent string
+		location  string
+	}{
+		{"http://example.com/news?id=1", iPhoneUserAgent, "http://example.com/m/news?id=1"},
+		{"http://example.com/news", iPadUserAgent, "http://example.com/m/news"},
+		{"http://example.com/m/news", desktopUserAgent, "http://example.com/news"},
+		{"http://example.com/m", desktopUserAgent, "http://example.com/"},
+		{"http://example.com/m/news", iPhoneUserAgent, ""},
+		{"http://example.com/mobile", desktopUserAgent, ""},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", test.url, nil)
+		r.Header.Set("User-Agent", test.userAgent)
+		rec := httptest.NewRecorder()
+		rd.Handler(http.NotFoundHandler()).ServeHTTP(rec, r)
+		if location := rec.Header().Get("Location"); test.location != location {
+			t.Errorf("For %s, expected redirect to %q got %q", test.url, test.location, location)
+		}
+	}
+}
+
+func TestRedirectorUnsafeRequests(t *testing.T) {
+	rd := NewRedirector(nil, "m.example.com", "www.example.com")
+
+	tests := []struct {
+		method   string
+		proto    string
+		location string
+	}{
+		{"GET", "https", "https://m.example.com/news"},
+		{"HEAD", "", "http://m.example.com/news"},
+		{"POST", "", ""},
+		{"GET", "https://evil.example/?", "http://m.example.com/news"},
+		{"GET", "javascript", "http://m.example.com/news"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, "http://www.example.com/news", nil)
+		r.Header.Set("User-Agent", iPhoneUserAgent)
+		if "" != test.proto {
+			r.Header.Set("X-Forwarded-Proto", test.proto)
+		}
+		rec := httptest.NewRecorder()
+		rd.Handler(http.NotFoundHandler()).ServeHTTP(rec, r)
+		if location := rec.Header().Get("Location"); test.location != location {
+			t.Errorf("For %s with X-Forwarded-Proto %q, expected redirect to %q got %q", test.method, test.proto, test.location, location)
+		}
+		if strings.Contains(rec.Header().Get("Link"), "evil") {
+			t.Errorf("Unexpected link %q", rec.Header().Get("Link"))
+		}
+	}
+}
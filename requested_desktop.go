@@ -0,0 +1,47 @@
+package mobiledetect
+
+import (
+	"strings"
+
+	"github.com/houseme/mobiledetect/ua"
+)
+
+// ActualDeviceType returns the class of the device itself, ignoring overrides: DeviceType, unless
+// a phone or a tablet asks for the desktop site, DeviceType then being Desktop. Chrome for Android then sends a Linux x86_64
+// User-Agent but keeps its Sec-CH-UA-Platform and Sec-CH-UA-Platform-Version hints, an iPad
+// sends a Macintosh User-Agent which only the touch points measured by the Beacon tell apart.
+func (md *MobileDetect) ActualDeviceType() DeviceType {
+	detected := md.detectedDeviceType()
+	if DeviceTypeDesktop == detected && md.mobileRequestingDesktop() {
+		detected = DeviceTypeMobile
+	}
+	if measured := md.measuredDeviceType(detected); "" != measured {
+		return measured
+	}
+	return detected
+}
+
+// RequestedDesktop reports whether a phone or a tablet asks for the desktop site: a Windows Phone
+// in desktop mode, or a desktop User-Agent on a device ActualDeviceType finds to be mobile.
+func (md *MobileDetect) RequestedDesktop() bool {
+	if md.IsKey(DESKTOPMODE) {
+		return true
+	}
+	return DeviceTypeDesktop == md.detectedDeviceType() && DeviceTypeDesktop != md.ActualDeviceType()
+}
+
+// mobileRequestingDesktop reports whether the client hints of a desktop User-Agent are those of a mobile device.
+func (md *MobileDetect) mobileRequestingDesktop() bool {
+	if md.IsKey(DESKTOPMODE) || "?1" == strings.TrimSpace(md.header("Sec-CH-UA-Mobile")) {
+		return true
+	}
+	platform := hintToken(md.header("Sec-CH-UA-Platform"))
+	if name := platformNames[platform]; ua.Android == name || ua.IOS == name {
+		return true
+	}
+	// Chrome on Linux sends an empty platform version, on Android the Android version.
+	if "" == platform || ua.Linux == platformNames[platform] {
+		return ua.Linux == ua.New(md.userAgent).ShortOS() && "" != hintToken(md.header("Sec-CH-UA-Platform-Version"))
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+const chromeDesktopSiteUserAgent = `Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36`
+
+func TestRequestedDesktop(t *testing.T) {
+	tests := []struct {
+		name         string
+		userAgent    string
+		headers      map[string]string
+		capabilities *Capabilities
+		deviceType   DeviceType
+		actual       DeviceType
+		requested    bool
+	}{
+		{"Chrome Android desktop site", chromeDesktopSiteUserAgent, map[string]string{"Sec-CH-UA-Mobile": "?0", "Sec-CH-UA-Platform": `"Android"`}, nil, DeviceTypeDesktop, DeviceTypeMobile, true},
+		{"Chrome Android desktop site on a tablet", chromeDesktopSiteUserAgent, map[string]string{"Sec-CH-UA-Platform": `"Android"`}, &Capabilities{Touch: true, MaxTouchPoints: 10, ScreenWidth: 800, ScreenHeight: 1280}, DeviceTypeDesktop, DeviceTypeTablet, true},
+		{"Android platform version", chromeDesktopSiteUserAgent, map[string]string{"Sec-CH-UA-Platform": `"Linux"`, "Sec-CH-UA-Platform-Version": `"14.0.0"`}, nil, DeviceTypeDesktop, DeviceTypeMobile, true},
+		{"Linux desktop", chromeDesktopSiteUserAgent, map[string]string{"Sec-CH-UA-Platform": `"Linux"`, "Sec-CH-UA-Platform-Version": `""`}, nil, DeviceTypeDesktop, DeviceTypeDesktop, false},
+		{"mobile hint", desktopUserAgent, map[string]string{"Sec-CH-UA-Mobile": "?1"}, nil, DeviceTypeDesktop, DeviceTypeMobile, true},
+		{"Mac", macSafariUserAgent, nil, nil, DeviceTypeDesktop, DeviceTypeDesktop, false},
+		{"iPad desktop mode", macSafariUserAgent, nil, &Capabilities{Touch: true, MaxTouchPoints: 5, ScreenWidth: 820, ScreenHeight: 1180}, DeviceTypeDesktop, DeviceTypeTablet, true},
+		{"Windows Phone desktop mode", `Mozilla/5.0 (Windows NT 6.2; ARM; Trident/7.0; Touch; rv:11.0; WPDesktop; Lumia 1520) like Gecko`, nil, nil, DeviceTypeDesktop, DeviceTypeMobile, true},
+		{"iPhone", iPhoneUserAgent, nil, nil, DeviceTypeMobile, DeviceTypeMobile, false},
+		{"desktop", desktopUserAgent, nil, nil, DeviceTypeDesktop, DeviceTypeDesktop, false},
+	}
+	for _, test := range tests {
+		md := New(newTestRequest(test.userAgent, test.headers), nil)
+		if nil != test.capabilities {
+			md.SetCapabilities(*test.capabilities)
+		}
+		if deviceType := md.DeviceType(); test.deviceType != deviceType {
+			t.Errorf("%s: expected device type %s got %s", test.name, test.deviceType, deviceType)
+		}
+		if actual := md.ActualDeviceType(); test.actual != actual {
+			t.Errorf("%s: expected actual device type %s got %s", test.name, test.actual, actual)
+		}
+		if requested := md.RequestedDesktop(); test.requested != requested {
+			t.Errorf("%s: expected requested desktop %v got %v", test.name, test.requested, requested)
+		}
+	}
+}
+
+func TestActualDeviceTypeIgnoresOverride(t *testing.T) {
+	md := New(newTestRequest(iPhoneUserAgent, nil), nil).SetOverride(DeviceTypeDesktop)
+	if DeviceTypeMobile != md.ActualDeviceType() || md.RequestedDesktop() {
+		t.Errorf("Expected an overridden iPhone to stay a phone, got %s %v", md.ActualDeviceType(), md.RequestedDesktop())
+	}
+}
@@ -0,0 +1,97 @@
+package mobiledetect
+
+import (
+	"sort"
+)
+
+// Result is a snapshot of everything detected about a request, e.g. for logging or JSON output.
+// Mobile is IsMobile, but for the smart TVs which DeviceType classifies as desktops.
+type Result struct {
+	UserAgent        string            `json:"userAgent"`
+	DeviceType       DeviceType        `json:"deviceType"`
+	Overridden       bool              `json:"overridden"`
+	ActualDeviceType DeviceType        `json:"actualDeviceType"`
+	RequestedDesktop bool              `json:"requestedDesktop"`
+	Mobile           bool              `json:"mobile"`
+	Tablet           bool              `json:"tablet"`
+	Bot              bool              `json:"bot"`
+	Grade            string            `json:"grade"`
+	Vendor           string            `json:"vendor"`
+	Markup           Markup            `json:"markup"`
+	Browser          string            `json:"browser"`
+	BrowserVersion   string            `json:"browserVersion"`
+	OS               string            `json:"os"`
+	OSVersion        string            `json:"osVersion"`
+	Rules            []string          `json:"rules"`
+	Versions         map[string]string `json:"versions"`
+	Screen           Screen            `json:"screen"`
+	Constraints      Constraints       `json:"constraints"`
+	Preferences      Preferences       `json:"preferences"`
+	Anomalies        []Anomaly         `json:"anomalies"`
+	Capabilities     *Capabilities     `json:"capabilities,omitempty"`
+	Carrier          *Carrier          `json:"carrier,omitempty"`
+	ProxyBrowser     *ProxyBrowser     `json:"proxyBrowser,omitempty"`
+}
+
+// Result returns the detection result of the request.
+func (md *MobileDetect) Result() Result {
+	browser, browserVersion := md.Browser()
+	system, systemVersion := md.OS()
+	var carrier *Carrier
+	if c, ok := md.Carrier(); ok {
+		carrier = &c
+	}
+	var proxyBrowser *ProxyBrowser
+	if p, ok := md.ProxyBrowser(); ok {
+		proxyBrowser = &p
+	}
+	return Result{
+		UserAgent:        md.userAgent,
+		DeviceType:       md.DeviceType(),
+		Overridden:       md.Overridden(),
+		ActualDeviceType: md.ActualDeviceType(),
+		RequestedDesktop: md.RequestedDesktop(),
+		Mobile:           md.IsMobile() && !md.isTV(),
+		Tablet:           md.IsTablet(),
+		Bot:              md.IsBot(),
+		Grade:            md.MobileGrade(),
+		Vendor:           md.Vendor(),
+		Markup:           md.PreferredMarkup(),
+		Browser:          browser,
+		BrowserVersion:   browserVersion,
+		OS:               system,
+		OSVersion:        systemVersion,
+		Rules:            md.MatchedRules(),
+		Versions:         md.Versions(),
+		Screen:           md.Screen(),
+		Constraints:      md.Constraints(),
+		Preferences:      md.Preferences(),
+		Anomalies:        md.Anomalies(),
+		Capabilities:     md.capabilities,
+		Carrier:          carrier,
+		ProxyBrowser:     proxyBrowser,
+	}
+}
+
+// MatchedRules returns the sorted names of the rules matching the User-Agent, as accepted by Is.
+func (md *MobileDetect) MatchedRules() []string {
+	var names []string
+	for name := range md.rules.namesKeys {
+		if md.Is(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns the versions found in the User-Agent, by property name as accepted by Version.
+func (md *MobileDetect) Versions() map[string]string {
+	versions := make(map[string]string)
+	for name, key := range propertiesNameToVal {
+		if version := md.VersionKey(key); "" != version {
+			versions[name] = version
+		}
+	}
+	return versions
+}
@@ -0,0 +1,27 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestResult(t *testing.T) {
+	result := New(newTestRequest(iPhoneUserAgent, map[string]string{"Save-Data": "on"}), nil).Result()
+	if DeviceTypeMobile != result.DeviceType || !result.Mobile || result.Tablet || result.Bot {
+		t.Errorf("Unexpected classification: %+v", result)
+	}
+	if "Apple" != result.Vendor || !result.Constraints.SaveData {
+		t.Errorf("Unexpected vendor or constraints: %+v", result)
+	}
+	if "13_2_3" != result.Versions["iphone"] {
+		t.Errorf("Expected iphone version 13_2_3 got %q", result.Versions["iphone"])
+	}
+
+	var ios, iphone bool
+	for _, name := range result.Rules {
+		ios = ios || "ios" == name
+		iphone = iphone || "iphone" == name
+	}
+	if !ios || !iphone {
+		t.Errorf("Expected ios and iphone among the matched rules: %v", result.Rules)
+	}
+}
@@ -3,6 +3,13 @@ package mobiledetect
 // Upstream Version: 2.8.39
 // https://github.com/serbanghita/Mobile-Detect/blob/2.8.39/Mobile_Detect.php
 
+const (
+	// RulesVersion is the version of Mobile_Detect the rules are ported from.
+	RulesVersion = "2.8.39"
+	// RulesSource is where the rules are ported from.
+	RulesSource = "https://github.com/serbanghita/Mobile-Detect/blob/2.8.39/Mobile_Detect.php"
+)
+
 const (
 	IPHONE = iota
 	BLACKBERRY
@@ -52,6 +59,7 @@ const (
 	PRESTIGIOTABLET
 	LENOVOTABLET
 	DELLTABLET
+	XIAOMITABLET
 	YARVIKTABLET
 	MEDIONTABLET
 	ARNOVATABLET
@@ -199,6 +207,25 @@ const (
 	WEBKIT
 	CONSOLE
 	WATCH
+
+	WINDOWS = iota
+	MACOS
+	LINUX
+	CHROMEOS
+	FREEBSD
+
+	DESKTOPEDGE = iota
+	DESKTOPOPERA
+	DESKTOPVIVALDI
+	DESKTOPYANDEX
+	DESKTOPBRAVE
+	DESKTOPCHROME
+	DESKTOPFIREFOX
+	DESKTOPSAFARI
+
+	// The keys added after the upstream ones come last, so that the others keep their value.
+	KAIOS = iota
+	SMARTFEATUREOS
 )
 
 var (
@@ -750,6 +777,52 @@ var (
 		// Watch
 		`SM-V700`,
 	}
+	// Desktop operating systems and browsers are not part of the mobile detection rules either.
+	// The browser names such as chrome or firefox are the mobile rules, desktopchrome and
+	// desktopfirefox the desktop ones.
+	desktopOperatingSystems = [...]string{
+		// Windows 10 and 11 both send Windows NT 10.0, see WindowsRelease.
+		// WINDOWS:
+		`Windows NT|Win64|WOW64|Windows (95|98|ME)\b`,
+		// The iOS User-Agents are like Mac OS X, but never Macintosh but for iPads in desktop mode.
+		// MACOS:
+		`Macintosh|Mac_PowerPC`,
+		// LINUX:
+		`X11; Linux|X11; Ubuntu|Ubuntu|Fedora|Debian|Linux Mint|openSUSE|Arch Linux|CentOS|Red Hat|Gentoo|Manjaro`,
+		// CHROMEOS:
+		`\bCrOS\b`,
+		// FREEBSD:
+		`FreeBSD`,
+	}
+	// Most specific first: the Chromium based browsers send the Chrome and Safari tokens too.
+	desktopBrowsers = [...]string{
+		// DESKTOPEDGE:
+		`\bEdge?/[.0-9]+`,
+		// DESKTOPOPERA:
+		`\bOPR/[.0-9]+|Opera[ /][.0-9]+`,
+		// DESKTOPVIVALDI:
+		`Vivaldi/[.0-9]+`,
+		// DESKTOPYANDEX:
+		`YaBrowser/[.0-9]+`,
+		// Brave sends the Chrome User-Agent, and its brand in Sec-CH-UA.
+		// DESKTOPBRAVE:
+		`\bBrave\b`,
+		// DESKTOPCHROME:
+		`Chrome/[.0-9]+|Chromium/[.0-9]+`,
+		// DESKTOPFIREFOX:
+		`Firefox/[.0-9]+`,
+		// DESKTOPSAFARI:
+		`Version/[.0-9]+.*Safari/`,
+	}
+	// Feature phone operating systems are mobile detection rules, keyed after the others.
+	featurePhoneOperatingSystems = [...]string{
+		// @reference: https://www.kaiostech.com/
+		// KAIOS:
+		`\bKAIOS\b`,
+		// Nokia Series 30+ and its successor Smart Feature OS.
+		// SMARTFEATUREOS:
+		`Series30Plus|\bS30\+|SmartFeatureOS`,
+	}
 	nameToKey = map[string]int{
 		`iphone`:            IPHONE,
 		`blackberry`:        BLACKBERRY,
@@ -798,6 +871,7 @@ var (
 		`prestigiotablet`:   PRESTIGIOTABLET,
 		`lenovotablet`:      LENOVOTABLET,
 		`delltablet`:        DELLTABLET,
+		`xiaomitablet`:      XIAOMITABLET,
 		`yarviktablet`:      YARVIKTABLET,
 		`mediontablet`:      MEDIONTABLET,
 		`arnovatablet`:      ARNOVATABLET,
@@ -942,6 +1016,25 @@ var (
 		`webkit`:            WEBKIT,
 		`console`:           CONSOLE,
 		`watch`:             WATCH,
+		`windows`:           WINDOWS,
+		`macos`:             MACOS,
+		`linux`:             LINUX,
+		`chromeos`:          CHROMEOS,
+		`freebsd`:           FREEBSD,
+		`desktopedge`:       DESKTOPEDGE,
+		`desktopopera`:      DESKTOPOPERA,
+		`desktopvivaldi`:    DESKTOPVIVALDI,
+		`desktopyandex`:     DESKTOPYANDEX,
+		`desktopbrave`:      DESKTOPBRAVE,
+		`desktopchrome`:     DESKTOPCHROME,
+		`desktopfirefox`:    DESKTOPFIREFOX,
+		`desktopsafari`:     DESKTOPSAFARI,
+		`kaios`:             KAIOS,
+		`smartfeatureos`:    SMARTFEATUREOS,
+		// Brave, Vivaldi and Yandex have no mobile rule, their plain name is the desktop one.
+		`brave`:   DESKTOPBRAVE,
+		`vivaldi`: DESKTOPVIVALDI,
+		`yandex`:  DESKTOPYANDEX,
 	}
 )
 
@@ -953,13 +1046,18 @@ type rules struct {
 	operatingSystems [len(operatingSystems)]string
 	browsers         [len(browsers)]string
 	utilities        [len(utilities)]string
+	desktopOS        [len(desktopOperatingSystems)]string
+	desktopBrowsers  [len(desktopBrowsers)]string
+	featurePhoneOS   [len(featurePhoneOperatingSystems)]string
 	combined         []string
 }
 
 // NewRules creates a object with all rules necessary to figure out a browser from a User Agent string
 func NewRules() *rules {
 	rules := &rules{namesKeys: nameToKey, phoneDevices: phoneDevices, tabletDevices: tabletDevices,
-		operatingSystems: operatingSystems, browsers: browsers, utilities: utilities}
+		operatingSystems: operatingSystems, browsers: browsers, utilities: utilities,
+		desktopOS: desktopOperatingSystems, desktopBrowsers: desktopBrowsers,
+		featurePhoneOS: featurePhoneOperatingSystems}
 	rules.setMobileDetectionRules(nameToKey)
 	return rules
 }
@@ -1002,5 +1100,9 @@ func (r *rules) setMobileDetectionRules(nameToKey map[string]int) {
 		j++
 	}
 
+	for i, rule := range r.featurePhoneOS {
+		combined[KAIOS+i] = rule
+	}
+
 	r.combined = combined
 }
@@ -11,3 +11,17 @@ func TestGetMobileDetectionRules(t *testing.T) {
 		t.Logf("Values length should be the same (count %d, values %d)", count, valuesLength)
 	}
 }
+
+func TestRuleKeys(t *testing.T) {
+	rules := NewRules()
+	values := rules.mobileDetectionRules()
+	if ANDROIDOS != len(phoneDevices)+len(tabletDevices) || CHROME != ANDROIDOS+len(operatingSystems) || BOT != CHROME+len(browsers) {
+		t.Errorf("The upstream keys moved: ANDROIDOS %d, CHROME %d, BOT %d", ANDROIDOS, CHROME, BOT)
+	}
+	if WINDOWS != BOT+len(utilities) || DESKTOPEDGE != WINDOWS+len(desktopOperatingSystems) || KAIOS != DESKTOPEDGE+len(desktopBrowsers) {
+		t.Errorf("The added keys must follow the upstream ones: WINDOWS %d, DESKTOPEDGE %d, KAIOS %d", WINDOWS, DESKTOPEDGE, KAIOS)
+	}
+	if values[CHROME] != browsers[0] || values[KAIOS] != featurePhoneOperatingSystems[0] || values[SMARTFEATUREOS] != featurePhoneOperatingSystems[1] {
+		t.Error("The keys do not match their rules")
+	}
+}
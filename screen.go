@@ -0,0 +1,126 @@
+package mobiledetect
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Screen describes the layout the client renders into, as reported by the viewport
+// and DPR client hints, measured by the Beacon or read from the User-Agent, or the
+// defaults for the detected DeviceType when they are missing.
+type Screen struct {
+	// ViewportWidth and ViewportHeight are the layout viewport in CSS pixels.
+	ViewportWidth  int
+	ViewportHeight int
+	// Width is the intrinsic width in physical pixels of the requested resource
+	// (Sec-CH-Width), zero when the client did not send it.
+	Width int
+	// DPR is the device pixel ratio.
+	DPR float64
+	// Hinted reports whether any of the values came from the request headers, the Beacon or the User-Agent.
+	Hinted bool
+}
+
+// DefaultScreens holds the values used for a device type when the client doesn't send hints.
+// Device types without an entry use the desktop values.
+var DefaultScreens = map[DeviceType]Screen{
+	DeviceTypeMobile:  {ViewportWidth: 360, ViewportHeight: 640, DPR: 2},
+	DeviceTypeTablet:  {ViewportWidth: 768, ViewportHeight: 1024, DPR: 2},
+	DeviceTypeDesktop: {ViewportWidth: 1366, ViewportHeight: 768, DPR: 1},
+	// QVGA, the screen of most feature phones.
+	DeviceTypeFeaturePhone: {ViewportWidth: 240, ViewportHeight: 320, DPR: 1},
+	// The 6" e-ink screen of the Kindle experimental browser.
+	DeviceTypeEReader: {ViewportWidth: 600, ViewportHeight: 800, DPR: 1},
+}
+
+// Screen returns the viewport and pixel density hints of the request.
+// Sec-CH-* hints take precedence over the legacy Viewport-Width and DPR headers.
+func (md *MobileDetect) Screen() Screen {
+	s := Screen{
+		ViewportWidth:  int(hintNumber(md.header("Sec-CH-Viewport-Width"), md.header("Viewport-Width"))),
+		ViewportHeight: int(hintNumber(md.header("Sec-CH-Viewport-Height"))),
+		Width:          int(hintNumber(md.header("Sec-CH-Width"))),
+		DPR:            math.Min(hintNumber(md.header("Sec-CH-DPR"), md.header("DPR")), maxDPR),
+	}
+	if c, ok := md.Capabilities(); ok {
+		if s.ViewportWidth <= 0 {
+			s.ViewportWidth = c.ViewportWidth
+		}
+		if s.ViewportHeight <= 0 {
+			s.ViewportHeight = c.ViewportHeight
+		}
+		if s.DPR <= 0 {
+			s.DPR = c.DPR
+		}
+	}
+	if s.ViewportWidth <= 0 && s.ViewportHeight <= 0 {
+		if ua, ok := md.ScreenFromUA(); ok && ua.Width > 0 {
+			s.ViewportWidth, s.ViewportHeight = ua.Width, ua.Height
+			if s.DPR <= 0 {
+				s.DPR = 1
+			}
+		}
+	}
+	s.Hinted = s.ViewportWidth > 0 || s.ViewportHeight > 0 || s.Width > 0 || s.DPR > 0
+
+	defaults, ok := DefaultScreens[md.DeviceType()]
+	if !ok {
+		defaults = DefaultScreens[DeviceTypeDesktop]
+	}
+	if s.ViewportWidth <= 0 {
+		s.ViewportWidth = defaults.ViewportWidth
+	}
+	if s.ViewportHeight <= 0 {
+		s.ViewportHeight = defaults.ViewportHeight
+	}
+	if s.DPR <= 0 {
+		s.DPR = defaults.DPR
+	}
+	return s
+}
+
+// BestWidth picks the rendition to serve out of the given image breakpoints: the smallest
+// one covering the physical pixels the client needs, or the largest one if none does.
+// It returns 0 when there are no breakpoints.
+func (s Screen) BestWidth(breakpoints []int) int {
+	if len(breakpoints) == 0 {
+		return 0
+	}
+	need := s.Width
+	if need <= 0 {
+		dpr := s.DPR
+		if dpr <= 0 {
+			dpr = 1
+		}
+		need = int(math.Ceil(float64(s.ViewportWidth) * dpr))
+	}
+
+	sorted := append([]int(nil), breakpoints...)
+	sort.Ints(sorted)
+	for _, width := range sorted {
+		if width >= need {
+			return width
+		}
+	}
+	return sorted[len(sorted)-1]
+}
+
+// maxHint bounds the numeric hints, like the Beacon bounds the screen sizes it accepts.
+const maxHint = 100000
+
+// maxDPR is the highest device pixel ratio a client can claim.
+const maxDPR = 10
+
+// hintNumber returns the first of the header values that parses as a positive number,
+// ignoring the non-finite ones and clamping the others to maxHint.
+func hintNumber(values ...string) float64 {
+	for _, v := range values {
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if nil == err && n > 0 && !math.IsInf(n, 1) {
+			return math.Min(n, maxHint)
+		}
+	}
+	return 0
+}
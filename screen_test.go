@@ -0,0 +1,80 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"testing"
+)
+
+const (
+	iPhoneUserAgent  = `Mozilla/5.0 (iPhone; CPU iPhone OS 13_2_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.3 Mobile/15E148 Safari/604.1`
+	iPadUserAgent    = `Mozilla/5.0 (iPad; CPU OS 12_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/12.1 Mobile/15E148 Safari/604.1`
+	desktopUserAgent = `Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36`
+)
+
+func newTestRequest(userAgent string, headers map[string]string) *http.Request {
+	r, _ := http.NewRequest("GET", "http://example.com/", nil)
+	r.Header.Set("User-Agent", userAgent)
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestScreen(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		headers   map[string]string
+		expected  Screen
+	}{
+		{iPhoneUserAgent, nil, Screen{ViewportWidth: 360, ViewportHeight: 640, DPR: 2}},
+		{iPadUserAgent, nil, Screen{ViewportWidth: 768, ViewportHeight: 1024, DPR: 2}},
+		{desktopUserAgent, nil, Screen{ViewportWidth: 1366, ViewportHeight: 768, DPR: 1}},
+		{desktopUserAgent, map[string]string{"Sec-CH-Viewport-Width": "1920", "Sec-CH-Viewport-Height": "1080", "Sec-CH-DPR": "1.5"},
+			Screen{ViewportWidth: 1920, ViewportHeight: 1080, DPR: 1.5, Hinted: true}},
+		{iPhoneUserAgent, map[string]string{"Viewport-Width": "414", "DPR": "3"},
+			Screen{ViewportWidth: 414, ViewportHeight: 640, DPR: 3, Hinted: true}},
+		{iPhoneUserAgent, map[string]string{"Sec-CH-Viewport-Width": "390", "Viewport-Width": "414", "Sec-CH-Width": "600"},
+			Screen{ViewportWidth: 390, ViewportHeight: 640, Width: 600, DPR: 2, Hinted: true}},
+		{iPhoneUserAgent, map[string]string{"Sec-CH-DPR": "bogus"}, Screen{ViewportWidth: 360, ViewportHeight: 640, DPR: 2}},
+		{iPhoneUserAgent, map[string]string{"Sec-CH-DPR": "inf", "Sec-CH-Viewport-Width": "NaN"}, Screen{ViewportWidth: 360, ViewportHeight: 640, DPR: 2}},
+		{iPhoneUserAgent, map[string]string{"Sec-CH-DPR": "50", "Sec-CH-Viewport-Width": "1e300"},
+			Screen{ViewportWidth: 100000, ViewportHeight: 640, DPR: 10, Hinted: true}},
+	}
+
+	for _, test := range tests {
+		got := New(newTestRequest(test.userAgent, test.headers), nil).Screen()
+		if test.expected != got {
+			t.Errorf("For userAgent %s and headers %v, expected %+v got %+v", test.userAgent, test.headers, test.expected, got)
+		}
+	}
+}
+
+func TestScreenFromHTTPHeaders(t *testing.T) {
+	detect := New(httpRequest, nil)
+	detect.SetHTTPHeaders(map[string]string{"HTTP_SEC_CH_VIEWPORT_WIDTH": "412"})
+	if 412 != detect.Screen().ViewportWidth {
+		t.Errorf("Viewport width was not read from the http headers: %+v", detect.Screen())
+	}
+}
+
+func TestScreenBestWidth(t *testing.T) {
+	breakpoints := []int{1280, 320, 640, 960}
+	tests := []struct {
+		screen   Screen
+		expected int
+	}{
+		{Screen{ViewportWidth: 360, DPR: 2}, 960},
+		{Screen{ViewportWidth: 320, DPR: 1}, 320},
+		{Screen{ViewportWidth: 1920, DPR: 1}, 1280},
+		{Screen{ViewportWidth: 360, DPR: 2, Width: 500}, 640},
+		{Screen{ViewportWidth: 300}, 320},
+	}
+	for _, test := range tests {
+		if got := test.screen.BestWidth(breakpoints); test.expected != got {
+			t.Errorf("For screen %+v, expected %d got %d", test.screen, test.expected, got)
+		}
+	}
+	if 0 != (Screen{ViewportWidth: 360}).BestWidth(nil) {
+		t.Error("Best width without breakpoints should be 0")
+	}
+}
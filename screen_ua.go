@@ -0,0 +1,46 @@
+package mobiledetect
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// UAScreen is the screen described by the User-Agent of some feature phones, Windows Mobile
+// and i-mode devices.
+type UAScreen struct {
+	// Width and Height are the screen size in pixels, zero when unknown.
+	Width  int
+	Height int
+	// Columns and Rows are the character-cell grid of i-mode devices, zero when unknown.
+	Columns int
+	Rows    int
+}
+
+var (
+	// Resolution/480x800, Res/240x320.
+	uaResolutionRegex = regexp.MustCompile(`(?i)\bRes(?:olution)?/(\d{2,4})[x*](\d{2,4})\b`)
+	// (c100;TB;W24H16) of DoCoMo i-mode browsers.
+	uaGridRegex = regexp.MustCompile(`\bW(\d{1,3})H(\d{1,3})\b`)
+	// 240x320, as in Windows Mobile PPC; 240x320 or UP.Browser ... 176x220.
+	uaSizeRegex = regexp.MustCompile(`(?i)(?:^|[\s;(/])(\d{3,4})[x*](\d{3,4})(?:$|[\s;)])`)
+)
+
+// ScreenFromUA extracts the screen tokens of the User-Agent: Resolution/WxH, the WxH size
+// and the i-mode WcolsHrows grid. It reports false when the User-Agent has none.
+func (md *MobileDetect) ScreenFromUA() (UAScreen, bool) {
+	var s UAScreen
+	if m := uaResolutionRegex.FindStringSubmatch(md.userAgent); nil != m {
+		s.Width, s.Height = atoi(m[1]), atoi(m[2])
+	} else if m := uaSizeRegex.FindStringSubmatch(md.userAgent); nil != m {
+		s.Width, s.Height = atoi(m[1]), atoi(m[2])
+	}
+	if m := uaGridRegex.FindStringSubmatch(md.userAgent); nil != m {
+		s.Columns, s.Rows = atoi(m[1]), atoi(m[2])
+	}
+	return s, s.Width > 0 || s.Columns > 0
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
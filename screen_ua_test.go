@@ -0,0 +1,40 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestScreenFromUA(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		expected  UAScreen
+		ok        bool
+	}{
+		{`SAMSUNG-SGH-E250/1.0 Profile/MIDP-2.0 Configuration/CLDC-1.1 UP.Browser/6.2.3.3.c.1.101 (GUI) MMP/2.0 176x220`, UAScreen{Width: 176, Height: 220}, true},
+		{`Mozilla/4.0 (compatible; MSIE 6.0; Windows CE; IEMobile 7.11) Sprint:PPC6800; 240x320`, UAScreen{Width: 240, Height: 320}, true},
+		{`Mozilla/5.0 (Linux; U; Android 2.3.4; en-us; Resolution/480x800) AppleWebKit/533.1 (KHTML, like Gecko) Version/4.0 Mobile Safari/533.1`, UAScreen{Width: 480, Height: 800}, true},
+		{`DoCoMo/2.0 N905i(c100;TB;W24H16)`, UAScreen{Columns: 24, Rows: 16}, true},
+		{`DoCoMo/2.0 P07A3(c500;TB;W24H15) 480x854`, UAScreen{Width: 480, Height: 854, Columns: 24, Rows: 15}, true},
+		{iPhoneUserAgent, UAScreen{}, false},
+		{desktopUserAgent, UAScreen{}, false},
+	}
+	for _, test := range tests {
+		screen, ok := New(newTestRequest(test.userAgent, nil), nil).ScreenFromUA()
+		if test.ok != ok || test.expected != screen {
+			t.Errorf("For %s, expected %+v %t got %+v %t", test.userAgent, test.expected, test.ok, screen, ok)
+		}
+	}
+}
+
+func TestScreenFallsBackToUA(t *testing.T) {
+	userAgent := `Mozilla/4.0 (compatible; MSIE 6.0; Windows CE; IEMobile 7.11) Sprint:PPC6800; 240x320`
+	s := New(newTestRequest(userAgent, nil), nil).Screen()
+	if 240 != s.ViewportWidth || 320 != s.ViewportHeight || 1 != s.DPR || !s.Hinted {
+		t.Errorf("Unexpected screen: %+v", s)
+	}
+
+	s = New(newTestRequest(userAgent, map[string]string{"Sec-CH-Viewport-Width": "300"}), nil).Screen()
+	if 300 != s.ViewportWidth {
+		t.Errorf("Client hints should take precedence: %+v", s)
+	}
+}
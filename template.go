@@ -0,0 +1,90 @@
+package mobiledetect
+
+import (
+	"html/template"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// TemplateFuncs returns the device functions for the templates rendering r:
+//
+//	isMobile, isTablet        the device type, tablets are mobile too, e-readers are tablets
+//	isEReader                 the e-ink device type
+//	is "iOS"                  Is
+//	version "Android"         Version
+//	grade                     MobileGrade
+//	deviceType                DeviceType
+//	vendor                    Vendor
+//	supports "webp"           Supports, unknown being false
+//
+// The MobileDetect stored in the request context is used when there is one.
+// A nil request gives functions describing an unknown desktop, which is enough to parse templates.
+func TemplateFuncs(r *http.Request) template.FuncMap {
+	if nil == r {
+		return New(&http.Request{}, nil).TemplateFuncs()
+	}
+	return detect(r, nil).TemplateFuncs()
+}
+
+// TemplateFuncs returns the device functions bound to md, see the TemplateFuncs function.
+func (md *MobileDetect) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"isMobile": func() bool {
+			deviceType := md.DeviceType()
+			return DeviceTypeDesktop != deviceType && DeviceTypeBot != deviceType
+		},
+		"isTablet": func() bool {
+			deviceType := md.DeviceType()
+			return DeviceTypeTablet == deviceType || DeviceTypeEReader == deviceType
+		},
+		"isEReader": func() bool {
+			return DeviceTypeEReader == md.DeviceType()
+		},
+		"is": func(key string) bool {
+			return md.Is(key)
+		},
+		"version": func(name string) string {
+			return md.Version(name)
+		},
+		"grade": md.MobileGrade,
+		"deviceType": func() string {
+			return string(md.DeviceType())
+		},
+		"vendor": md.Vendor,
+		"supports": func(feature string) bool {
+			return Supported == md.Supports(feature)
+		},
+	}
+}
+
+// Render executes the variant of the named template for the device type of the request:
+// "page.mobile.tmpl" or "page.tablet.tmpl" when t defines it, then the variants of the
+// DefaultFallbacks of the device type, like DeviceMux does, "page.tmpl" otherwise.
+// t is cloned for every request, so it must not have been executed itself;
+// parse it with TemplateFuncs(nil) so that the device functions are defined.
+func Render(w io.Writer, r *http.Request, t *template.Template, name string, data interface{}) error {
+	md := detect(r, nil)
+	tmpl, err := t.Clone()
+	if nil != err {
+		return err
+	}
+	tmpl.Funcs(md.TemplateFuncs())
+	return tmpl.ExecuteTemplate(w, templateVariant(tmpl, name, md.DeviceType()), data)
+}
+
+// templateVariant returns the name of the template for the device type, falling back to name.
+func templateVariant(t *template.Template, name string, deviceType DeviceType) string {
+	ext := path.Ext(name)
+	for _, d := range fallbackChain(nil, deviceType) {
+		if DeviceTypeDesktop == d {
+			return name
+		}
+		variant := strings.TrimSuffix(name, ext) + "." + strings.ToLower(string(d)) + ext
+		if nil != t.Lookup(variant) {
+			return variant
+		}
+	}
+	return name
+}
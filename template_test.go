@@ -0,0 +1,51 @@
+package mobiledetect
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("funcs").Funcs(TemplateFuncs(nil)).Parse(
+		`{{deviceType}} {{isMobile}} {{isTablet}} {{is "iOS"}} {{version "iPhone"}} {{grade}} {{vendor}} {{supports "webp"}}`))
+
+	expectedResults := map[string]string{
+		iPhoneUserAgent:  "Mobile true false true 13_2_3 A Apple false",
+		iPadUserAgent:    "Tablet true true true  A Apple false",
+		desktopUserAgent: "Desktop false false false  B  true",
+	}
+	for userAgent, expected := range expectedResults {
+		var buf bytes.Buffer
+		tmpl, _ := tmpl.Clone()
+		if err := tmpl.Funcs(TemplateFuncs(newTestRequest(userAgent, nil))).Execute(&buf, nil); nil != err {
+			t.Fatal(err)
+		}
+		if expected != buf.String() {
+			t.Errorf("For userAgent %s, expected %q got %q", userAgent, expected, buf.String())
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl := template.Must(template.New("root").Funcs(TemplateFuncs(nil)).Parse(
+		`{{define "page.tmpl"}}desktop {{vendor}}{{end}}{{define "page.mobile.tmpl"}}mobile {{vendor}}{{end}}`))
+
+	expectedResults := map[string]string{
+		iPhoneUserAgent: "mobile Apple",
+		// Tablets fall back to the mobile variant, as in DefaultFallbacks.
+		iPadUserAgent:    "mobile Apple",
+		desktopUserAgent: "desktop ",
+		// Feature phones fall back to the mobile variant.
+		`Nokia6300/2.0 (05.00) Profile/MIDP-2.0 Configuration/CLDC-1.1`: "mobile ",
+	}
+	for userAgent, expected := range expectedResults {
+		var buf bytes.Buffer
+		if err := Render(&buf, newTestRequest(userAgent, nil), tmpl, "page.tmpl", nil); nil != err {
+			t.Fatal(err)
+		}
+		if expected != buf.String() {
+			t.Errorf("For userAgent %s, expected %q got %q", userAgent, expected, buf.String())
+		}
+	}
+}
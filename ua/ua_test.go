@@ -123,8 +123,8 @@ func TestParse(t *testing.T) {
 	}
 }
 
-// ExampleParse .
-func ExampleParse() {
+// ExampleUserAgent_Parse .
+func ExampleUserAgent_Parse() {
 	userAgents := []string{
 		// Mac
 		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/603.3.8 (KHTML, like Gecko) Version/10.1.2 Safari/603.3.8",
@@ -0,0 +1,231 @@
+package mobiledetect
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// UAProf is the part of a UAProf (User Agent Profile) RDF document the detector uses.
+type UAProf struct {
+	Vendor string
+	Model  string
+	// ScreenWidth and ScreenHeight are the screen size in pixels.
+	ScreenWidth  int
+	ScreenHeight int
+	// Accept lists the MIME types the device supports (CcppAccept).
+	Accept []string
+}
+
+// Accepts reports whether the device supports the MIME type.
+func (p *UAProf) Accepts(mimeType string) bool {
+	for _, accept := range p.Accept {
+		if strings.EqualFold(accept, mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseUAProf parses a UAProf RDF/XML document. Properties are matched by their local name,
+// whatever the version of the schema.
+func ParseUAProf(r io.Reader) (*UAProf, error) {
+	p := &UAProf{}
+	d := xml.NewDecoder(r)
+	d.Strict = false
+
+	var stack []string
+	inAccept := false
+	for {
+		token, err := d.Token()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return nil, fmt.Errorf("mobiledetect: parsing UAProf: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			if "CcppAccept" == t.Name.Local {
+				inAccept = true
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			if "CcppAccept" == t.Name.Local {
+				inAccept = false
+			}
+		case xml.CharData:
+			value := strings.TrimSpace(string(t))
+			if "" == value || len(stack) == 0 {
+				continue
+			}
+			switch current := stack[len(stack)-1]; {
+			case "Vendor" == current && "" == p.Vendor:
+				p.Vendor = value
+			case "Model" == current && "" == p.Model:
+				p.Model = value
+			case "ScreenSize" == current && 0 == p.ScreenWidth:
+				p.ScreenWidth, p.ScreenHeight = parseScreenSize(value)
+			case "li" == current && inAccept:
+				p.Accept = append(p.Accept, value)
+			}
+		}
+	}
+	return p, nil
+}
+
+// parseScreenSize parses a WIDTHxHEIGHT screen size.
+func parseScreenSize(value string) (int, int) {
+	parts := strings.SplitN(strings.ToLower(value), "x", 2)
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	width, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if nil != err {
+		return 0, 0
+	}
+	height, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if nil != err {
+		return 0, 0
+	}
+	return width, height
+}
+
+// UAProfLoader opens the UAProf document of a profile URL.
+type UAProfLoader interface {
+	Load(profileURL string) (io.ReadCloser, error)
+}
+
+// UAProfLoaderFunc adapts a function to UAProfLoader.
+type UAProfLoaderFunc func(profileURL string) (io.ReadCloser, error)
+
+// Load calls f.
+func (f UAProfLoaderFunc) Load(profileURL string) (io.ReadCloser, error) {
+	return f(profileURL)
+}
+
+// FSLoader loads the profiles cached in fsys, keyed by the host and path of their URL:
+// http://nds1.nds.nokia.com/uaprof/N6300r100.xml is read from nds1.nds.nokia.com/uaprof/N6300r100.xml.
+// fsys may be a directory (see DirLoader) or an archive, a *zip.Reader being an fs.FS.
+func FSLoader(fsys fs.FS) UAProfLoader {
+	return UAProfLoaderFunc(func(profileURL string) (io.ReadCloser, error) {
+		name, err := uaprofName(profileURL)
+		if nil != err {
+			return nil, err
+		}
+		return fsys.Open(name)
+	})
+}
+
+// uaprofName returns the host and cleaned path of the profile URL, the query being ignored.
+func uaprofName(profileURL string) (string, error) {
+	u, err := url.Parse(profileURL)
+	if nil != err {
+		return "", err
+	}
+	name := strings.ToLower(u.Host) + path.Clean("/"+u.Path)
+	if "" == u.Host || !fs.ValidPath(name) {
+		return "", fmt.Errorf("mobiledetect: invalid UAProf URL %q", profileURL)
+	}
+	return name, nil
+}
+
+// DirLoader loads the profiles cached in a directory, see FSLoader.
+func DirLoader(dir string) UAProfLoader {
+	return FSLoader(os.DirFS(dir))
+}
+
+// UAProfStore parses the profiles provided by its loader and caches them by host and path,
+// see FSLoader. Profiles which can't be loaded are not cached, the loader being expected to be local.
+type UAProfStore struct {
+	Loader UAProfLoader
+	// MaxProfiles caps the cached profiles, DefaultUAProfCacheSize when zero.
+	MaxProfiles int
+
+	mu       sync.Mutex
+	profiles map[string]*UAProf
+}
+
+// DefaultUAProfCacheSize is the number of profiles a UAProfStore caches by default.
+const DefaultUAProfCacheSize = 1000
+
+// NewUAProfStore creates a UAProfStore over the given loader.
+func NewUAProfStore(loader UAProfLoader) *UAProfStore {
+	return &UAProfStore{Loader: loader}
+}
+
+// Profile returns the profile of the URL.
+func (s *UAProfStore) Profile(profileURL string) (*UAProf, error) {
+	key, err := uaprofName(profileURL)
+	if nil != err {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	p, ok := s.profiles[key]
+	s.mu.Unlock()
+	if ok {
+		return p, nil
+	}
+
+	rc, err := s.Loader.Load(profileURL)
+	if nil != err {
+		return nil, err
+	}
+	defer rc.Close()
+	p, err = ParseUAProf(rc)
+	if nil != err {
+		return nil, err
+	}
+
+	max := s.MaxProfiles
+	if max <= 0 {
+		max = DefaultUAProfCacheSize
+	}
+	s.mu.Lock()
+	if nil == s.profiles {
+		s.profiles = make(map[string]*UAProf)
+	}
+	for name := range s.profiles {
+		if len(s.profiles) < max {
+			break
+		}
+		delete(s.profiles, name)
+	}
+	s.profiles[key] = p
+	s.mu.Unlock()
+	return p, nil
+}
+
+// UAProfURL returns the profile URL sent in the X-Wap-Profile or Profile header, if any.
+func (md *MobileDetect) UAProfURL() string {
+	for _, name := range []string{"X-Wap-Profile", "Profile", "Wap-Profile"} {
+		if value := md.header(name); "" != value {
+			fields := strings.FieldsFunc(value, func(r rune) bool { return ' ' == r || '"' == r || ',' == r })
+			if len(fields) > 0 {
+				return fields[0]
+			}
+		}
+	}
+	return ""
+}
+
+// UAProf returns the profile of the device from the store, nil when the request has none.
+func (md *MobileDetect) UAProf(s *UAProfStore) (*UAProf, error) {
+	profileURL := md.UAProfURL()
+	if "" == profileURL {
+		return nil, nil
+	}
+	return s.Profile(profileURL)
+}
@@ -0,0 +1,135 @@
+package mobiledetect
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const nokia6300Profile = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlns:prf="http://www.openmobilealliance.org/tech/profiles/UAPROF/ccppschema-20021212#">
+	<rdf:Description rdf:ID="Profile">
+		<prf:component>
+			<rdf:Description rdf:ID="HardwarePlatform">
+				<prf:Vendor>Nokia</prf:Vendor>
+				<prf:Model>6300</prf:Model>
+				<prf:ScreenSize>240x320</prf:ScreenSize>
+			</rdf:Description>
+		</prf:component>
+		<prf:component>
+			<rdf:Description rdf:ID="SoftwarePlatform">
+				<prf:CcppAccept>
+					<rdf:Bag>
+						<rdf:li>application/vnd.wap.xhtml+xml</rdf:li>
+						<rdf:li>image/jpeg</rdf:li>
+						<rdf:li>text/vnd.wap.wml</rdf:li>
+					</rdf:Bag>
+				</prf:CcppAccept>
+			</rdf:Description>
+		</prf:component>
+	</rdf:Description>
+</rdf:RDF>`
+
+const nokia6300ProfileURL = "http://nds1.nds.nokia.com/uaprof/N6300r100.xml"
+
+func TestParseUAProf(t *testing.T) {
+	p, err := ParseUAProf(strings.NewReader(nokia6300Profile))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if "Nokia" != p.Vendor || "6300" != p.Model || 240 != p.ScreenWidth || 320 != p.ScreenHeight {
+		t.Errorf("Unexpected profile: %+v", p)
+	}
+	if len(p.Accept) != 3 || !p.Accepts("text/vnd.wap.wml") || p.Accepts("image/webp") {
+		t.Errorf("Unexpected accepted types: %v", p.Accept)
+	}
+
+	if _, err := ParseUAProf(strings.NewReader(`<rdf:RDF><prf:Vendor>`)); nil == err {
+		t.Error("Expected an error for a truncated document")
+	}
+}
+
+func TestUAProfStore(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("nds1.nds.nokia.com/uaprof/N6300r100.xml")
+	io.WriteString(w, nokia6300Profile)
+	zw.Close()
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "nds1.nds.nokia.com", "uaprof"), 0755)
+	os.WriteFile(filepath.Join(dir, "nds1.nds.nokia.com", "uaprof", "N6300r100.xml"), []byte(nokia6300Profile), 0644)
+
+	loaders := map[string]UAProfLoader{
+		"map": FSLoader(fstest.MapFS{"nds1.nds.nokia.com/uaprof/N6300r100.xml": {Data: []byte(nokia6300Profile)}}),
+		"zip": FSLoader(zr),
+		"dir": DirLoader(dir),
+	}
+	for name, loader := range loaders {
+		s := NewUAProfStore(loader)
+		p, err := s.Profile(nokia6300ProfileURL)
+		if nil != err || "6300" != p.Model {
+			t.Errorf("%s: unexpected profile %+v, %v", name, p, err)
+		}
+		if _, err := s.Profile("http://example.com/missing.xml"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("%s: expected a not exist error got %v", name, err)
+		}
+		if _, err := s.Profile("http://nds1.nds.nokia.com/../../etc/passwd"); nil == err {
+			t.Errorf("%s: expected an error for an escaping URL", name)
+		}
+	}
+}
+
+func TestUAProfCache(t *testing.T) {
+	loads := 0
+	s := NewUAProfStore(UAProfLoaderFunc(func(profileURL string) (io.ReadCloser, error) {
+		loads++
+		return io.NopCloser(strings.NewReader(nokia6300Profile)), nil
+	}))
+	for i := 0; i < 3; i++ {
+		s.Profile(nokia6300ProfileURL)
+		s.Profile(nokia6300ProfileURL + "?" + strconv.Itoa(i))
+	}
+	if 1 != loads || 1 != len(s.profiles) {
+		t.Errorf("Expected 1 load and 1 cached profile got %d and %d", loads, len(s.profiles))
+	}
+
+	s.MaxProfiles = 10
+	for i := 0; i < 100; i++ {
+		s.Profile("http://example.com/" + strconv.Itoa(i) + ".xml")
+	}
+	if 10 != len(s.profiles) {
+		t.Errorf("Expected 10 cached profiles got %d", len(s.profiles))
+	}
+}
+
+func TestDetectorUAProf(t *testing.T) {
+	s := NewUAProfStore(FSLoader(fstest.MapFS{"nds1.nds.nokia.com/uaprof/N6300r100.xml": {Data: []byte(nokia6300Profile)}}))
+	userAgent := `Nokia6300/2.0 (05.00) Profile/MIDP-2.0 Configuration/CLDC-1.1`
+	for _, header := range []string{"X-Wap-Profile", "Profile"} {
+		md := New(newTestRequest(userAgent, map[string]string{header: `"` + nokia6300ProfileURL + `", "1-abcdef"`}), nil)
+		if nokia6300ProfileURL != md.UAProfURL() {
+			t.Errorf("%s: unexpected profile URL %q", header, md.UAProfURL())
+		}
+		if p, err := md.UAProf(s); nil != err || "Nokia" != p.Vendor {
+			t.Errorf("%s: unexpected profile %+v, %v", header, p, err)
+		}
+	}
+
+	if p, err := New(newTestRequest(iPhoneUserAgent, nil), nil).UAProf(s); nil != p || nil != err {
+		t.Errorf("Expected no profile got %+v, %v", p, err)
+	}
+}
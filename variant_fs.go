@@ -0,0 +1,173 @@
+package mobiledetect
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// DefaultVariantSuffixes are the suffixes of the device-specific variants of a file:
+// index.html has index.mobile.html, index.tablet.html, index.featurephone.html and index.ereader.html variants.
+var DefaultVariantSuffixes = map[DeviceType]string{
+	DeviceTypeMobile:       "mobile",
+	DeviceTypeTablet:       "tablet",
+	DeviceTypeFeaturePhone: "featurephone",
+	DeviceTypeEReader:      "ereader",
+}
+
+// VariantFS resolves files to their device-specific variants when present,
+// trying the device type, then its fallbacks, then the file itself.
+type VariantFS struct {
+	// Root holds the files and their variants.
+	Root http.FileSystem
+	// Suffixes replaces DefaultVariantSuffixes when set.
+	Suffixes map[DeviceType]string
+	// Fallbacks replaces DefaultFallbacks when set.
+	Fallbacks map[DeviceType][]DeviceType
+	// Override, when set, lets users force the device type served by FileServer.
+	Override *Override
+
+	rules *rules
+}
+
+// NewVariantFS creates a VariantFS over root using the given rules, or the default ones when nil.
+func NewVariantFS(root http.FileSystem, rules *rules) *VariantFS {
+	return &VariantFS{Root: root, rules: rules}
+}
+
+// NewVariantFSFromFS creates a VariantFS over an fs.FS, such as an embed.FS.
+func NewVariantFSFromFS(fsys fs.FS, rules *rules) *VariantFS {
+	return NewVariantFS(http.FS(fsys), rules)
+}
+
+// Device returns the file system resolving names to the variants of the given device type.
+func (v *VariantFS) Device(deviceType DeviceType) http.FileSystem {
+	return deviceFS{v: v, deviceType: deviceType}
+}
+
+type deviceFS struct {
+	v          *VariantFS
+	deviceType DeviceType
+}
+
+func (d deviceFS) Open(name string) (http.File, error) {
+	f, _, err := d.v.open(name, d.deviceType)
+	return f, err
+}
+
+// Variants returns the names tried for the given device type, in order.
+func (v *VariantFS) Variants(name string, deviceType DeviceType) []string {
+	suffixes := v.Suffixes
+	if nil == suffixes {
+		suffixes = DefaultVariantSuffixes
+	}
+	var names []string
+	for _, d := range fallbackChain(v.Fallbacks, deviceType) {
+		if suffix := suffixes[d]; "" != suffix {
+			names = appendUnique(names, variantName(name, suffix))
+		}
+	}
+	return appendUnique(names, name)
+}
+
+// open returns the first variant found along with its name. Directories only resolve to themselves.
+func (v *VariantFS) open(name string, deviceType DeviceType) (http.File, string, error) {
+	for _, variant := range v.Variants(name, deviceType) {
+		f, err := v.Root.Open(variant)
+		if nil != err {
+			if errors.Is(err, fs.ErrNotExist) && variant != name {
+				continue
+			}
+			return nil, "", err
+		}
+		if variant != name {
+			if d, err := f.Stat(); nil != err || d.IsDir() {
+				f.Close()
+				continue
+			}
+		}
+		return f, variant, nil
+	}
+	return nil, "", os.ErrNotExist
+}
+
+// variantName inserts the suffix before the extension: index.html becomes index.mobile.html.
+func variantName(name, suffix string) string {
+	ext := path.Ext(name)
+	if strings.Contains(ext, "/") {
+		ext = ""
+	}
+	return strings.TrimSuffix(name, ext) + "." + suffix + ext
+}
+
+func appendUnique(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+// FileServer serves the files of v, picking the variant matching the device type of each request.
+// Responses vary on User-Agent and carry a weak ETag and a Last-Modified of the variant served,
+// so conditional requests are answered per variant. Directories are served by their index.html.
+func FileServer(v *VariantFS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md := detect(r, v.rules)
+		v.Override.Apply(w, r, md)
+
+		w.Header().Add("Vary", "User-Agent")
+		if nil != v.Override {
+			w.Header().Add("Vary", "Cookie")
+		}
+
+		name := path.Clean("/" + r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/") {
+			name = path.Join(name, "index.html")
+		}
+
+		f, variant, err := v.open(name, md.DeviceType())
+		if nil != err {
+			serveError(w, err)
+			return
+		}
+		defer f.Close()
+
+		d, err := f.Stat()
+		if nil != err {
+			serveError(w, err)
+			return
+		}
+		if d.IsDir() {
+			http.Redirect(w, r, withQuery(path.Base(r.URL.Path)+"/", r.URL.RawQuery), http.StatusMovedPermanently)
+			return
+		}
+
+		w.Header().Set("ETag", variantETag(variant, d))
+		http.ServeContent(w, r, variant, d.ModTime(), f)
+	})
+}
+
+// variantETag builds a weak ETag from the name, size and modification time of the variant.
+func variantETag(name string, d fs.FileInfo) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf(`W/"%x-%x-%x"`, h.Sum32(), d.Size(), d.ModTime().UnixNano())
+}
+
+func serveError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		http.Error(w, "404 page not found", http.StatusNotFound)
+	case errors.Is(err, fs.ErrPermission):
+		http.Error(w, "403 Forbidden", http.StatusForbidden)
+	default:
+		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
+	}
+}
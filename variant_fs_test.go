@@ -0,0 +1,117 @@
+package mobiledetect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testVariantFS() *VariantFS {
+	modTime := time.Unix(1600000000, 0)
+	return NewVariantFSFromFS(fstest.MapFS{
+		"index.html":             {Data: []byte("desktop"), ModTime: modTime},
+		"index.mobile.html":      {Data: []byte("mobile"), ModTime: modTime},
+		"docs/index.html":        {Data: []byte("docs"), ModTime: modTime},
+		"docs/index.tablet.html": {Data: []byte("docs tablet"), ModTime: modTime},
+		"app.js":                 {Data: []byte("app"), ModTime: modTime},
+	}, nil)
+}
+
+func TestVariantFSVariants(t *testing.T) {
+	v := testVariantFS()
+	tests := []struct {
+		name       string
+		deviceType DeviceType
+		expected   []string
+	}{
+		{"/index.html", DeviceTypeTablet, []string{"/index.tablet.html", "/index.mobile.html", "/index.html"}},
+		{"/index.html", DeviceTypeDesktop, []string{"/index.html"}},
+		{"/v1.2/app", DeviceTypeMobile, []string{"/v1.2/app.mobile", "/v1.2/app"}},
+	}
+	for _, test := range tests {
+		variants := v.Variants(test.name, test.deviceType)
+		if len(variants) != len(test.expected) {
+			t.Errorf("For %s on %s, expected %v got %v", test.name, test.deviceType, test.expected, variants)
+			continue
+		}
+		for i := range variants {
+			if test.expected[i] != variants[i] {
+				t.Errorf("For %s on %s, expected %v got %v", test.name, test.deviceType, test.expected, variants)
+				break
+			}
+		}
+	}
+}
+
+func TestFileServer(t *testing.T) {
+	h := FileServer(testVariantFS())
+	tests := []struct {
+		url       string
+		userAgent string
+		code      int
+		body      string
+	}{
+		{"/", iPhoneUserAgent, http.StatusOK, "mobile"},
+		{"/", iPadUserAgent, http.StatusOK, "mobile"},
+		{"/", desktopUserAgent, http.StatusOK, "desktop"},
+		{"/docs/", iPadUserAgent, http.StatusOK, "docs tablet"},
+		{"/docs/", iPhoneUserAgent, http.StatusOK, "docs"},
+		{"/app.js", iPhoneUserAgent, http.StatusOK, "app"},
+		{"/docs", iPhoneUserAgent, http.StatusMovedPermanently, ""},
+		{"/missing.js", iPhoneUserAgent, http.StatusNotFound, ""},
+	}
+
+	etags := make(map[string]string)
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", test.url, nil)
+		r.Header.Set("User-Agent", test.userAgent)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if test.code != rec.Code {
+			t.Errorf("For %s, expected status %d got %d", test.url, test.code, rec.Code)
+			continue
+		}
+		if http.StatusOK != rec.Code {
+			continue
+		}
+		if body := rec.Body.String(); test.body != body {
+			t.Errorf("For %s, expected %q got %q", test.url, test.body, body)
+		}
+		if "User-Agent" != rec.Header().Get("Vary") {
+			t.Errorf("For %s, expected Vary: User-Agent got %q", test.url, rec.Header().Get("Vary"))
+		}
+		etag := rec.Header().Get("ETag")
+		if other, ok := etags[etag]; ok && other != test.body {
+			t.Errorf("ETag %s is shared by %q and %q", etag, other, test.body)
+		}
+		etags[etag] = test.body
+	}
+}
+
+func TestFileServerConditional(t *testing.T) {
+	h := FileServer(testVariantFS())
+	r := httptest.NewRequest("GET", "/index.html", nil)
+	r.Header.Set("User-Agent", iPhoneUserAgent)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	etag := rec.Header().Get("ETag")
+	if "" == etag || "" == rec.Header().Get("Last-Modified") {
+		t.Fatalf("Missing validators: %v", rec.Header())
+	}
+
+	r.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if http.StatusNotModified != rec.Code {
+		t.Errorf("Expected 304 for the same variant got %d", rec.Code)
+	}
+
+	r.Header.Set("User-Agent", desktopUserAgent)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if http.StatusOK != rec.Code {
+		t.Errorf("Expected 200 for another variant got %d", rec.Code)
+	}
+}
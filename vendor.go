@@ -0,0 +1,83 @@
+package mobiledetect
+
+import (
+	"strings"
+)
+
+// vendors names the manufacturer behind the device rules whose name doesn't say it plainly.
+// The other rules are named after their vendor, see Vendor.
+var vendors = map[int]string{
+	IPHONE:            "Apple",
+	IPAD:              "Apple",
+	PIXEL:             "Google",
+	NEXUS:             "Google",
+	NEXUSTABLET:       "Google",
+	GOOGLETABLET:      "Google",
+	HTC:               "HTC",
+	HTCTABLET:         "HTC",
+	LG:                "LG",
+	LGTABLET:          "LG",
+	BLACKBERRY:        "BlackBerry",
+	BLACKBERRYTABLET:  "BlackBerry",
+	IMOBILE:           "i-mobile",
+	IMOBILETABLET:     "i-mobile",
+	ONEPLUS:           "OnePlus",
+	INQ:               "INQ",
+	KINDLE:            "Amazon",
+	SURFACETABLET:     "Microsoft",
+	NOKIALUMIATABLET:  "Nokia",
+	HPTABLET:          "HP",
+	NOOKTABLET:        "Barnes & Noble",
+	PLAYSTATIONTABLET: "Sony",
+	POCKETBOOKTABLET:  "PocketBook",
+	TREKSTORTABLET:    "TrekStor",
+	MITABLET:          "Xiaomi",
+	HUDL:              "Tesco",
+	NECTABLET:         "NEC",
+	HCLTABLET:         "HCL",
+	DPSTABLET:         "DPS",
+	ECSTABLET:         "ECS",
+	SKKTABLET:         "SKK",
+	JXDTABLET:         "JXD",
+	FX2TABLET:         "FX2",
+	AOCTABLET:         "AOC",
+	MSITABLET:         "MSI",
+	BQTABLET:          "bq",
+	// Rules shared by several vendors.
+	MIDTABLET:      "",
+	SMITTABLET:     "",
+	ROCKCHIPTABLET: "",
+	MEDIATEKTABLET: "",
+	GENERICPHONE:   "",
+	GENERICTABLET:  "",
+}
+
+// Vendor returns the manufacturer of the device, as told by the first phone or tablet rule
+// matching the User-Agent, tablets first. It returns an empty string when no rule
+// matches or the rule is not specific to a vendor.
+func (md *MobileDetect) Vendor() string {
+	for key := IPAD; key < ANDROIDOS; key++ {
+		if md.IsKey(key) {
+			return md.vendorName(key)
+		}
+	}
+	for key := IPHONE; key < IPAD; key++ {
+		if md.IsKey(key) {
+			return md.vendorName(key)
+		}
+	}
+	return ""
+}
+
+func (md *MobileDetect) vendorName(key int) string {
+	if vendor, ok := vendors[key]; ok {
+		return vendor
+	}
+	for name, k := range md.rules.namesKeys {
+		if k == key {
+			name = strings.TrimSuffix(name, "tablet")
+			return strings.ToUpper(name[:1]) + name[1:]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,24 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestVendor(t *testing.T) {
+	expectedResults := map[string]string{
+		iPhoneUserAgent: "Apple",
+		iPadUserAgent:   "Apple",
+		`Mozilla/5.0 (Linux; Android 4.3; GT-I9300 Build/JSS15J) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.125 Mobile Safari/537.36`:                   "Samsung",
+		`Mozilla/5.0 (Linux; Android 9; Pixel 3 Build/PQ1A.181105.017.A1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.110 Mobile Safari/537.36`:          "Google",
+		`Mozilla/5.0 (Linux; U; Android 4.0.3; en-us; KFTT Build/IML74K) AppleWebKit/535.19 (KHTML, like Gecko) Silk/3.4 Mobile Safari/535.19 Silk-Accelerated=true`: "Amazon",
+		`Mozilla/5.0 (Linux; Android 4.2.2; ARCHOS 101 XS 2 Build/JDQ39) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/30.0.1599.92 Safari/537.36`:                   "Archos",
+		desktopUserAgent: "",
+	}
+	detect := New(httpRequest, nil)
+	for userAgent, expected := range expectedResults {
+		detect.SetUserAgent(userAgent)
+		if vendor := detect.Vendor(); expected != vendor {
+			t.Errorf("For userAgent %s, expected vendor %q got %q", userAgent, expected, vendor)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package mobiledetect
+
+import (
+	"strconv"
+	"strings"
+)
+
+// versionParts splits a version on dots and underscores into its leading numeric components:
+// "13_2_3" gives [13 2 3], "120.0.6099.71b" gives [120 0 6099 71].
+func versionParts(version string) []int {
+	var parts []int
+	for _, field := range strings.FieldsFunc(version, func(r rune) bool { return '.' == r || '_' == r }) {
+		end := 0
+		for end < len(field) && field[end] >= '0' && field[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(field[:end])
+		if nil != err {
+			break
+		}
+		parts = append(parts, n)
+		if end < len(field) {
+			break
+		}
+	}
+	return parts
+}
+
+// compareVersions compares two versions component by component, missing components being zero.
+// It returns -1, 0 or 1 as a is lower than, equal to or greater than b.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionWithin reports whether version belongs to the line, or an older one: 15.7.9 is within 15.
+func versionWithin(version, line string) bool {
+	parts := versionParts(version)
+	for i, n := range versionParts(line) {
+		var x int
+		if i < len(parts) {
+			x = parts[i]
+		}
+		if x != n {
+			return x < n
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package mobiledetect
+
+import (
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"13_2_3", "13.2.3", 0},
+		{"10", "10.0.0", 0},
+		{"9.1", "10", -1},
+		{"120.0.6099.71b", "120.0.6099.70", 1},
+		{"", "1", -1},
+	}
+	for _, test := range tests {
+		if actual := compareVersions(test.a, test.b); test.expected != actual {
+			t.Errorf("compareVersions(%q, %q): expected %d got %d", test.a, test.b, test.expected, actual)
+		}
+	}
+}
+
+func TestVersionWithin(t *testing.T) {
+	tests := []struct {
+		version, line string
+		expected      bool
+	}{
+		{"15.7.9", "15", true},
+		{"14.8", "15", true},
+		{"16.0", "15", false},
+		{"6.1", "6.1", true},
+		{"6.10", "6.1", false},
+	}
+	for _, test := range tests {
+		if actual := versionWithin(test.version, test.line); test.expected != actual {
+			t.Errorf("versionWithin(%q, %q): expected %t got %t", test.version, test.line, test.expected, actual)
+		}
+	}
+}